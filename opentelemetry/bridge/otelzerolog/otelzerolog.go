@@ -0,0 +1,107 @@
+// Package otelzerolog sends the zerolog events to an Opentelemetry logger
+// provider, correlated with the active span.
+//
+// [Hook] emits the level and the message of the events only, zerolog not
+// exposing the fields of an event to its hooks. [Writer] parses the JSON
+// lines written by zerolog instead and emits the fields, including the
+// context fields of the logger, as attributes of the log records.
+package otelzerolog
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the zerolog field holding the trace id of the active span
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the zerolog field holding the span id of the active span
+	SpanIDKey = "span_id"
+	// TraceFlagsKey is the zerolog field holding the trace flags of the active
+	// span in hex, 01 when it is sampled
+	TraceFlagsKey = "trace_flags"
+)
+
+// Hook emits a log record for every zerolog event. The active span is
+// read from the context attached to the event with [zerolog.Event.Ctx].
+// The fields of the event are not part of the record, use [Writer] to
+// send them.
+type Hook struct {
+	logger log.Logger
+}
+
+// NewHook creates a hook emitting through the logger named name of the
+// provider. Use global.GetLoggerProvider() to emit through the provider
+// registered by opentelemetry.Setup.
+func NewHook(provider log.LoggerProvider, name string) *Hook {
+	return &Hook{
+		logger: provider.Logger(name),
+	}
+}
+
+// Run implements zerolog.Hook.
+func (h *Hook) Run(e *zerolog.Event, level zerolog.Level, message string) {
+	if !e.Enabled() {
+		return
+	}
+	ctx := e.GetCtx()
+	addSpanFields(e, ctx)
+
+	var record log.Record
+	record.SetTimestamp(time.Now())
+	record.SetBody(log.StringValue(message))
+	record.SetSeverity(severity(level))
+	record.SetSeverityText(level.String())
+	if !h.logger.Enabled(ctx, record) {
+		return
+	}
+	h.logger.Emit(ctx, record)
+}
+
+// SpanHook adds the trace_id, span_id and trace_flags fields of the span
+// active in the context attached to the event, for the [Writer] to correlate
+// the records.
+type SpanHook struct{}
+
+// Run implements zerolog.Hook.
+func (SpanHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	if !e.Enabled() {
+		return
+	}
+	addSpanFields(e, e.GetCtx())
+}
+
+func addSpanFields(e *zerolog.Event, ctx context.Context) {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if spanCtx.IsValid() {
+		e.Str(TraceIDKey, spanCtx.TraceID().String())
+		e.Str(SpanIDKey, spanCtx.SpanID().String())
+		e.Str(TraceFlagsKey, spanCtx.TraceFlags().String())
+	}
+}
+
+func severity(level zerolog.Level) log.Severity {
+	switch level {
+	case zerolog.TraceLevel:
+		return log.SeverityTrace
+	case zerolog.DebugLevel:
+		return log.SeverityDebug
+	case zerolog.InfoLevel:
+		return log.SeverityInfo
+	case zerolog.WarnLevel:
+		return log.SeverityWarn
+	case zerolog.ErrorLevel:
+		return log.SeverityError
+	case zerolog.FatalLevel:
+		return log.SeverityFatal
+	case zerolog.PanicLevel:
+		return log.SeverityFatal4
+	default:
+		return log.SeverityUndefined
+	}
+}
@@ -0,0 +1,88 @@
+package otelzerolog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHook(t *testing.T) {
+	recorder := logtest.NewRecorder()
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf).Hook(NewHook(recorder, "test"))
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+	logger.Warn().Ctx(ctx).Msg("payment failed")
+
+	require.Contains(t, buf.String(), `"trace_id":"`+spanCtx.TraceID().String()+`"`)
+	require.Contains(t, buf.String(), `"span_id":"`+spanCtx.SpanID().String()+`"`)
+	require.Contains(t, buf.String(), `"trace_flags":"01"`)
+
+	result := recorder.Result()
+	require.Len(t, result, 1)
+	require.Len(t, result[0].Records, 1)
+	record := result[0].Records[0]
+	require.Equal(t, "payment failed", record.Body().AsString())
+	require.Equal(t, log.SeverityWarn, record.Severity())
+	require.Equal(t, spanCtx, trace.SpanContextFromContext(record.Context()))
+}
+
+func TestHookWithoutSpan(t *testing.T) {
+	recorder := logtest.NewRecorder()
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf).Hook(NewHook(recorder, "test"))
+
+	logger.Info().Msg("started")
+
+	require.NotContains(t, buf.String(), TraceIDKey)
+	result := recorder.Result()
+	require.Len(t, result[0].Records, 1)
+	require.Equal(t, log.SeverityInfo, result[0].Records[0].Severity())
+}
+
+func TestWriter(t *testing.T) {
+	recorder := logtest.NewRecorder()
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(zerolog.MultiLevelWriter(buf, NewWriter(recorder, "test"))).
+		Hook(SpanHook{}).
+		With().Str("service", "payments").Logger()
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+	logger.Error().Ctx(ctx).Int("amount", 100).Bool("retried", true).Msg("payment failed")
+
+	require.Contains(t, buf.String(), `"payment failed"`)
+	result := recorder.Result()
+	require.Len(t, result[0].Records, 1)
+	record := result[0].Records[0]
+	require.Equal(t, "payment failed", record.Body().AsString())
+	require.Equal(t, log.SeverityError, record.Severity())
+	require.Equal(t, spanCtx.TraceID(), trace.SpanContextFromContext(record.Context()).TraceID())
+	require.Equal(t, spanCtx.SpanID(), trace.SpanContextFromContext(record.Context()).SpanID())
+	require.True(t, trace.SpanContextFromContext(record.Context()).IsSampled())
+
+	attributes := map[string]log.Value{}
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attributes[kv.Key] = kv.Value
+		return true
+	})
+	require.Len(t, attributes, 3)
+	require.Equal(t, "payments", attributes["service"].AsString())
+	require.Equal(t, int64(100), attributes["amount"].AsInt64())
+	require.True(t, attributes["retried"].AsBool())
+}
@@ -0,0 +1,143 @@
+package otelzerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Writer emits a log record for every JSON line written by a zerolog
+// logger, with the fields of the line as attributes. The level, message and
+// time fields are mapped to the severity, body and timestamp of the record,
+// and the trace_id, span_id and trace_flags fields added by [SpanHook] to
+// its span context. Combine it with the output of the logger through
+// [zerolog.MultiLevelWriter].
+type Writer struct {
+	logger log.Logger
+}
+
+var _ zerolog.LevelWriter = (*Writer)(nil)
+
+// NewWriter creates a writer emitting through the logger named name of the
+// provider.
+func NewWriter(provider log.LoggerProvider, name string) *Writer {
+	return &Writer{
+		logger: provider.Logger(name),
+	}
+}
+
+// Write implements io.Writer, the level being read from the line.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. Lines which are not JSON
+// objects are emitted as the body of the record.
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	ctx := context.Background()
+	var record log.Record
+	record.SetObservedTimestamp(time.Now())
+
+	fields := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		record.SetBody(log.StringValue(string(bytes.TrimSpace(p))))
+		fields = nil
+	}
+
+	if text, ok := fields[zerolog.LevelFieldName].(string); ok {
+		if parsed, err := zerolog.ParseLevel(text); err == nil && level == zerolog.NoLevel {
+			level = parsed
+		}
+		delete(fields, zerolog.LevelFieldName)
+	}
+	if level != zerolog.NoLevel {
+		record.SetSeverity(severity(level))
+		record.SetSeverityText(level.String())
+	}
+	if message, ok := fields[zerolog.MessageFieldName].(string); ok {
+		record.SetBody(log.StringValue(message))
+		delete(fields, zerolog.MessageFieldName)
+	}
+	if timestamp, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		if parsed, err := time.Parse(zerolog.TimeFieldFormat, timestamp); err == nil {
+			record.SetTimestamp(parsed)
+			delete(fields, zerolog.TimestampFieldName)
+		}
+	}
+	if spanCtx, ok := spanContext(fields); ok {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, spanCtx)
+		delete(fields, TraceIDKey)
+		delete(fields, SpanIDKey)
+		delete(fields, TraceFlagsKey)
+	}
+
+	for key, value := range fields {
+		record.AddAttributes(log.KeyValue{Key: key, Value: toValue(value)})
+	}
+	if w.logger.Enabled(ctx, record) {
+		w.logger.Emit(ctx, record)
+	}
+	return len(p), nil
+}
+
+func spanContext(fields map[string]interface{}) (trace.SpanContext, bool) {
+	traceText, _ := fields[TraceIDKey].(string)
+	spanText, _ := fields[SpanIDKey].(string)
+	var config trace.SpanContextConfig
+	traceID, err := hex.DecodeString(traceText)
+	if err != nil || len(traceID) != len(config.TraceID) {
+		return trace.SpanContext{}, false
+	}
+	spanID, err := hex.DecodeString(spanText)
+	if err != nil || len(spanID) != len(config.SpanID) {
+		return trace.SpanContext{}, false
+	}
+	copy(config.TraceID[:], traceID)
+	copy(config.SpanID[:], spanID)
+	// the flags are optional, the span is not sampled without them.
+	if flagsText, ok := fields[TraceFlagsKey].(string); ok {
+		if flags, err := hex.DecodeString(flagsText); err == nil && len(flags) == 1 {
+			config.TraceFlags = trace.TraceFlags(flags[0])
+		}
+	}
+	spanCtx := trace.NewSpanContext(config)
+	return spanCtx, spanCtx.IsValid()
+}
+
+func toValue(value interface{}) log.Value {
+	switch v := value.(type) {
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return log.Int64Value(i)
+		}
+		f, _ := v.Float64()
+		return log.Float64Value(f)
+	case []interface{}:
+		values := make([]log.Value, 0, len(v))
+		for _, item := range v {
+			values = append(values, toValue(item))
+		}
+		return log.SliceValue(values...)
+	case map[string]interface{}:
+		kvs := make([]log.KeyValue, 0, len(v))
+		for key, item := range v {
+			kvs = append(kvs, log.KeyValue{Key: key, Value: toValue(item)})
+		}
+		return log.MapValue(kvs...)
+	default:
+		return log.Value{}
+	}
+}
@@ -9,10 +9,29 @@ var ErrTelemetryConfigMissing = errors.New("config for telemetry instance is not
 
 // Config is the root configuration for the OTEL observability stack
 type Config struct {
-	ServiceName string `mapstructure:"service_name" json:"service_name"`
-	Exporters   []Exporter
-	Metrics     *MetricsConfig
-	Trace       *TraceConfig
+	ServiceName string `mapstructure:"service_name" json:"service_name" yaml:"service_name"`
+	// Disabled turns the telemetry off, the global providers are left untouched
+	Disabled  bool           `mapstructure:"disabled" json:"disabled" yaml:"disabled"`
+	Exporters []Exporter     `mapstructure:"exporters" json:"exporters" yaml:"exporters"`
+	Metrics   *MetricsConfig `mapstructure:"metrics" json:"metrics" yaml:"metrics"`
+	Trace     *TraceConfig   `mapstructure:"traces" json:"traces" yaml:"traces"`
+	Logs      *LogsConfig    `mapstructure:"logs" json:"logs" yaml:"logs"`
+	// Resource describes the entity producing the telemetry data, beyond its service name
+	Resource *ResourceConfig `mapstructure:"resource" json:"resource" yaml:"resource"`
+	// Propagators are the names of the propagators of the trace context (tracecontext,
+	// baggage, b3, b3multi, jaeger, xray, ottrace or none), tracecontext and baggage if empty
+	Propagators []string `mapstructure:"propagators" json:"propagators" yaml:"propagators"`
+}
+
+// ResourceConfig has the attributes of the resource and the detectors
+// discovering the others. Detected attributes are overridden by the static
+// ones, themselves overridden by the service name of the Config.
+type ResourceConfig struct {
+	// Attributes are static attributes (exp: service.version, deployment.environment, service.namespace)
+	Attributes map[string]string `mapstructure:"attributes" json:"attributes" yaml:"attributes"`
+	// Detectors are applied in order, a detector overriding the attributes of the previous
+	// ones: env (OTEL_RESOURCE_ATTRIBUTES), host, process, os, container and k8s
+	Detectors []string `mapstructure:"detectors" json:"detectors" yaml:"detectors"`
 }
 
 type ExporterKind string
@@ -25,26 +44,93 @@ type ExporterKind string
 //
 // The Config is the configuration for this provider
 type Exporter struct {
-	Name   string
-	Kind   ExporterKind
-	Config map[string]interface{}
+	Name   string                 `mapstructure:"name" json:"name" yaml:"name"`
+	Kind   ExporterKind           `mapstructure:"kind" json:"kind" yaml:"kind"`
+	Config map[string]interface{} `mapstructure:"config" json:"config" yaml:"config"`
 }
 
 type MetricsConfig struct {
-	Exporters []string
+	Exporters []string `mapstructure:"exporters" json:"exporters" yaml:"exporters"`
+}
+
+type LogsConfig struct {
+	Exporters []string `mapstructure:"exporters" json:"exporters" yaml:"exporters"`
 }
 
 type TraceConfig struct {
-	Exporters  []string
-	SampleRate float64 `mapstructure:"sample_rate" json:"sample_rate"`
-}
-
-func Validate(cfg *Config) error {
-	if cfg.Exporters == nil || len(cfg.Exporters) == 0 {
-		return ErrNoExporter
-	}
-	if cfg.Metrics == nil && cfg.Trace == nil {
-		return ErrTelemetryConfigMissing
-	}
-	return nil
+	Exporters  []string `mapstructure:"exporters" json:"exporters" yaml:"exporters"`
+	SampleRate float64  `mapstructure:"sample_rate" json:"sample_rate" yaml:"sample_rate"`
+	// Sampler replaces the sampling driven by SampleRate when provided
+	Sampler *SamplerConfig `mapstructure:"sampler" json:"sampler" yaml:"sampler"`
+	// Processors configures the span processor of the exporters, by exporter name.
+	// Exporters without entry use a batch processor with the SDK defaults.
+	Processors map[string]SpanProcessorConfig `mapstructure:"processors" json:"processors" yaml:"processors"`
+}
+
+// SamplerConfig describes the sampler deciding which traces are recorded.
+type SamplerConfig struct {
+	// Type is one of always_on, always_off, traceidratio, ratelimited, parentbased_always_on,
+	// parentbased_always_off, parentbased_traceidratio (default) and parentbased_ratelimited
+	Type string `mapstructure:"type" json:"type" yaml:"type"`
+	// Ratio is the ratio of traces sampled by the traceidratio samplers and policies,
+	// the SampleRate of the trace config if not provided
+	Ratio *float64 `mapstructure:"ratio" json:"ratio" yaml:"ratio"`
+	// TracesPerSecond is the maximum number of traces sampled per second by the ratelimited samplers
+	TracesPerSecond float64 `mapstructure:"traces_per_second" json:"traces_per_second" yaml:"traces_per_second"`
+	// PerSpanName gives the TracesPerSecond budget to each root span name instead of sharing it
+	PerSpanName bool `mapstructure:"per_span_name" json:"per_span_name" yaml:"per_span_name"`
+	// RemoteParentSampled, RemoteParentNotSampled, LocalParentSampled and LocalParentNotSampled
+	// are the policies (always_on, always_off or traceidratio) of the parentbased samplers for
+	// the spans having a parent. They default to follow the decision of the parent.
+	RemoteParentSampled    string `mapstructure:"remote_parent_sampled" json:"remote_parent_sampled" yaml:"remote_parent_sampled"`
+	RemoteParentNotSampled string `mapstructure:"remote_parent_not_sampled" json:"remote_parent_not_sampled" yaml:"remote_parent_not_sampled"`
+	LocalParentSampled     string `mapstructure:"local_parent_sampled" json:"local_parent_sampled" yaml:"local_parent_sampled"`
+	LocalParentNotSampled  string `mapstructure:"local_parent_not_sampled" json:"local_parent_not_sampled" yaml:"local_parent_not_sampled"`
+	// Rules take the decision for the spans they match, in order, before the sampler of Type.
+	// For the parentbased samplers, they only apply to the root spans.
+	Rules []SamplingRule `mapstructure:"rules" json:"rules" yaml:"rules"`
+}
+
+// The types of SamplerConfig.
+const (
+	SamplerAlwaysOn                = "always_on"
+	SamplerAlwaysOff               = "always_off"
+	SamplerTraceIDRatio            = "traceidratio"
+	SamplerParentBasedAlwaysOn     = "parentbased_always_on"
+	SamplerParentBasedAlwaysOff    = "parentbased_always_off"
+	SamplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+	SamplerRateLimited             = "ratelimited"
+	SamplerParentBasedRateLimited  = "parentbased_ratelimited"
+)
+
+// SamplingRule samples the spans matching all its conditions. The conditions
+// are patterns where * matches any sequence of characters, evaluated against
+// what is known when the span starts.
+type SamplingRule struct {
+	// SpanName is the pattern of the span name
+	SpanName string `mapstructure:"span_name" json:"span_name" yaml:"span_name"`
+	// HTTPRoute is the pattern of the http.route attribute
+	HTTPRoute string `mapstructure:"http_route" json:"http_route" yaml:"http_route"`
+	// Attributes are the patterns of attribute values, by attribute key
+	Attributes map[string]string `mapstructure:"attributes" json:"attributes" yaml:"attributes"`
+	// Ratio is the ratio of the matching traces sampled, 1 if not provided
+	Ratio *float64 `mapstructure:"ratio" json:"ratio" yaml:"ratio"`
+}
+
+// SpanProcessorConfig configures how the spans are handed to an exporter.
+// Zero values keep the SDK defaults.
+type SpanProcessorConfig struct {
+	// Synchronous exports each span when it ends instead of batching them,
+	// meant for tests and CLI tools
+	Synchronous bool `mapstructure:"synchronous" json:"synchronous" yaml:"synchronous"`
+	// MaxQueueSize is the maximum number of spans buffered before being dropped (default 2048)
+	MaxQueueSize int `mapstructure:"max_queue_size" json:"max_queue_size" yaml:"max_queue_size"`
+	// MaxExportBatchSize is the maximum number of spans of an export (default 512)
+	MaxExportBatchSize int `mapstructure:"max_export_batch_size" json:"max_export_batch_size" yaml:"max_export_batch_size"`
+	// BatchTimeoutMs is the maximum delay between two exports (default 5000)
+	BatchTimeoutMs int `mapstructure:"batch_timeout_ms" json:"batch_timeout_ms" yaml:"batch_timeout_ms"`
+	// ExportTimeoutMs is the timeout of an export (default 30000)
+	ExportTimeoutMs int `mapstructure:"export_timeout_ms" json:"export_timeout_ms" yaml:"export_timeout_ms"`
+	// Blocking makes ending a span wait for room in the queue instead of dropping it
+	Blocking bool `mapstructure:"blocking" json:"blocking" yaml:"blocking"`
 }
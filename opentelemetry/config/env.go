@@ -0,0 +1,402 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EnvExporterOTLP is the exporter name of OTEL_*_EXPORTER configuring an
+	// opentelemetry exporter from the OTEL_EXPORTER_OTLP_* variables.
+	EnvExporterOTLP = "otlp"
+	// EnvExporterPrometheus is the exporter name of OTEL_METRICS_EXPORTER configuring
+	// a prometheus exporter from the OTEL_EXPORTER_PROMETHEUS_* variables.
+	EnvExporterPrometheus = "prometheus"
+	// EnvExporterConsole is the exporter name of OTEL_*_EXPORTER configuring a
+	// console exporter printing to the standard output.
+	EnvExporterConsole = "console"
+	// EnvExporterZipkin is the exporter name of OTEL_TRACES_EXPORTER configuring
+	// a zipkin exporter from the OTEL_EXPORTER_ZIPKIN_* variables.
+	EnvExporterZipkin = "zipkin"
+	// EnvExporterNone disables a signal when used in OTEL_*_EXPORTER.
+	EnvExporterNone = "none"
+)
+
+// envExporterKinds are the kinds of the exporters named in OTEL_*_EXPORTER.
+var envExporterKinds = map[string]ExporterKind{
+	EnvExporterOTLP:       "opentelemetry",
+	EnvExporterPrometheus: "prometheus",
+	EnvExporterConsole:    "console",
+	EnvExporterZipkin:     "zipkin",
+}
+
+// envExporterConfigs map the environment variables to the configuration
+// keys of the exporters, by exporter name.
+var envExporterConfigs = map[string][]envExporterVariable{
+	EnvExporterOTLP: {
+		{"OTEL_EXPORTER_OTLP_ENDPOINT", otlpEndpoint},
+		{"OTEL_EXPORTER_OTLP_PROTOCOL", stringValue("protocol")},
+		{"OTEL_EXPORTER_OTLP_INSECURE", boolValue("insecure")},
+		{"OTEL_EXPORTER_OTLP_CERTIFICATE", stringValue("ca_file")},
+		{"OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE", stringValue("cert_file")},
+		{"OTEL_EXPORTER_OTLP_CLIENT_KEY", stringValue("key_file")},
+		{"OTEL_EXPORTER_OTLP_HEADERS", otlpHeaders},
+		{"OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", stringValue("temporality_preference")},
+		{"OTEL_METRIC_EXPORT_INTERVAL", intValue("export_interval_ms")},
+		{"OTEL_METRIC_EXPORT_TIMEOUT", intValue("export_timeout_ms")},
+	},
+	EnvExporterPrometheus: {
+		{"OTEL_EXPORTER_PROMETHEUS_PORT", intValue("port")},
+	},
+	EnvExporterZipkin: {
+		{"OTEL_EXPORTER_ZIPKIN_ENDPOINT", stringValue("endpoint")},
+		{"OTEL_EXPORTER_ZIPKIN_TIMEOUT", intValue("timeout_ms")},
+	},
+}
+
+type envExporterVariable struct {
+	name  string
+	apply func(cfg map[string]interface{}, value string) error
+}
+
+// FromEnv creates a Config from the OTEL_* environment variables only. As
+// defined by the specification, the traces, metrics and logs are exported
+// with the otlp exporter unless OTEL_TRACES_EXPORTER, OTEL_METRICS_EXPORTER
+// and OTEL_LOGS_EXPORTER name others.
+func FromEnv() (*Config, error) {
+	return overlayEnv(&Config{}, func(name string) (string, bool) {
+		value, ok := os.LookupEnv(name)
+		if !ok && (name == "OTEL_TRACES_EXPORTER" || name == "OTEL_METRICS_EXPORTER" || name == "OTEL_LOGS_EXPORTER") {
+			return EnvExporterOTLP, true
+		}
+		return value, ok
+	})
+}
+
+// WithEnv returns a copy of cfg where the settings of the OTEL_* environment
+// variables which are set override the ones of cfg, so that the telemetry of a
+// deployment can be reconfigured without code changes:
+//   - OTEL_SDK_DISABLED disables the telemetry
+//   - OTEL_SERVICE_NAME sets the service name and OTEL_RESOURCE_ATTRIBUTES enables
+//     the env resource detector
+//   - OTEL_TRACES_EXPORTER, OTEL_METRICS_EXPORTER and OTEL_LOGS_EXPORTER replace
+//     the exporters of the signals with a comma separated list of otlp, prometheus
+//     (metrics only), zipkin (traces only), console or none to disable the signal
+//   - OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_PROTOCOL, OTEL_EXPORTER_OTLP_HEADERS,
+//     OTEL_EXPORTER_OTLP_INSECURE, OTEL_EXPORTER_OTLP_CERTIFICATE, OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE,
+//     OTEL_EXPORTER_OTLP_CLIENT_KEY, OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE,
+//     OTEL_METRIC_EXPORT_INTERVAL and OTEL_METRIC_EXPORT_TIMEOUT configure the otlp exporter
+//   - OTEL_EXPORTER_PROMETHEUS_PORT configures the prometheus exporter
+//   - OTEL_EXPORTER_ZIPKIN_ENDPOINT and OTEL_EXPORTER_ZIPKIN_TIMEOUT configure the
+//     zipkin exporter
+//   - OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG set the sampler
+//   - OTEL_PROPAGATORS sets the propagators
+//
+// The otlp, prometheus, zipkin and console exporters are the exporters of cfg
+// with these names, created if cfg does not declare them. The variables specific to a
+// signal (exp: OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) are not supported as an
+// exporter serves all the signals. The OTEL_BSP_* variables are honoured by
+// the span processors for the settings cfg leaves to the default.
+func WithEnv(cfg *Config) (*Config, error) {
+	return overlayEnv(cfg, os.LookupEnv)
+}
+
+func overlayEnv(cfg *Config, lookup func(string) (string, bool)) (*Config, error) {
+	out := *cfg
+	if value, ok := lookup("OTEL_SDK_DISABLED"); ok && value != "" {
+		disabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTEL_SDK_DISABLED: %w", err)
+		}
+		out.Disabled = disabled
+	}
+	if value, ok := lookup("OTEL_SERVICE_NAME"); ok && value != "" {
+		out.ServiceName = value
+	}
+	if value, ok := lookup("OTEL_RESOURCE_ATTRIBUTES"); ok && value != "" {
+		out.Resource = withEnvDetector(out.Resource)
+	}
+	if value, ok := lookup("OTEL_PROPAGATORS"); ok && value != "" {
+		out.Propagators = splitList(value)
+	}
+
+	exporters := map[string]bool{}
+	if value, ok := lookup("OTEL_TRACES_EXPORTER"); ok && value != "" {
+		names, err := envExporterNames("OTEL_TRACES_EXPORTER", value, exporters, EnvExporterOTLP, EnvExporterConsole, EnvExporterZipkin)
+		if err != nil {
+			return nil, err
+		}
+		if names == nil {
+			out.Trace = nil
+		} else {
+			trace := TraceConfig{SampleRate: 1}
+			if out.Trace != nil {
+				trace = *out.Trace
+			}
+			trace.Exporters = names
+			out.Trace = &trace
+		}
+	}
+	if value, ok := lookup("OTEL_METRICS_EXPORTER"); ok && value != "" {
+		names, err := envExporterNames("OTEL_METRICS_EXPORTER", value, exporters, EnvExporterOTLP, EnvExporterPrometheus, EnvExporterConsole)
+		if err != nil {
+			return nil, err
+		}
+		out.Metrics = nil
+		if names != nil {
+			out.Metrics = &MetricsConfig{Exporters: names}
+		}
+	}
+	if value, ok := lookup("OTEL_LOGS_EXPORTER"); ok && value != "" {
+		names, err := envExporterNames("OTEL_LOGS_EXPORTER", value, exporters, EnvExporterOTLP, EnvExporterConsole)
+		if err != nil {
+			return nil, err
+		}
+		out.Logs = nil
+		if names != nil {
+			out.Logs = &LogsConfig{Exporters: names}
+		}
+	}
+	for _, name := range []string{EnvExporterOTLP, EnvExporterPrometheus, EnvExporterConsole, EnvExporterZipkin} {
+		var err error
+		out.Exporters, err = overlayEnvExporter(out.Exporters, name, exporters[name], lookup)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if out.Trace != nil {
+		err := overlayEnvSampler(&out, lookup)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &out, nil
+}
+
+// envExporterNames returns the exporter names of an OTEL_*_EXPORTER
+// variable, nil if the signal is disabled, and records them in used.
+func envExporterNames(variable, value string, used map[string]bool, supported ...string) ([]string, error) {
+	names := splitList(value)
+	if len(names) == 1 && names[0] == EnvExporterNone {
+		return nil, nil
+	}
+	for _, name := range names {
+		found := false
+		for _, s := range supported {
+			found = found || s == name
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid %s: unsupported exporter %q", variable, name)
+		}
+		used[name] = true
+	}
+	return names, nil
+}
+
+// overlayEnvExporter applies the environment variables of the exporter
+// name to its configuration, adding the exporter when it is referenced by
+// OTEL_*_EXPORTER but not declared.
+func overlayEnvExporter(exporters []Exporter, name string, referenced bool, lookup func(string) (string, bool)) ([]Exporter, error) {
+	index := -1
+	for i, e := range exporters {
+		if e.Name == name {
+			index = i
+		}
+	}
+	if index == -1 && !referenced {
+		return exporters, nil
+	}
+
+	exporter := Exporter{Name: name, Kind: envExporterKinds[name]}
+	if index != -1 {
+		exporter = exporters[index]
+	}
+	exporterCfg := make(map[string]interface{}, len(exporter.Config))
+	for k, v := range exporter.Config {
+		exporterCfg[k] = v
+	}
+	for _, variable := range envExporterConfigs[name] {
+		value, ok := lookup(variable.name)
+		if !ok || value == "" {
+			continue
+		}
+		err := variable.apply(exporterCfg, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", variable.name, err)
+		}
+	}
+	exporter.Config = exporterCfg
+
+	out := make([]Exporter, len(exporters), len(exporters)+1)
+	copy(out, exporters)
+	if index == -1 {
+		return append(out, exporter), nil
+	}
+	out[index] = exporter
+	return out, nil
+}
+
+func overlayEnvSampler(cfg *Config, lookup func(string) (string, bool)) error {
+	samplerType, typeOk := lookup("OTEL_TRACES_SAMPLER")
+	typeOk = typeOk && samplerType != ""
+	arg, argOk := lookup("OTEL_TRACES_SAMPLER_ARG")
+	argOk = argOk && arg != ""
+	if !typeOk && !argOk {
+		return nil
+	}
+
+	trace := *cfg.Trace
+	cfg.Trace = &trace
+	var sampler SamplerConfig
+	if trace.Sampler != nil {
+		sampler = *trace.Sampler
+	}
+	if typeOk {
+		sampler.Type = samplerType
+	} else if trace.Sampler == nil {
+		// without sampler, the argument is the ratio of the default one.
+		ratio, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid OTEL_TRACES_SAMPLER_ARG: %w", err)
+		}
+		trace.SampleRate = ratio
+		return nil
+	}
+
+	rateLimited := sampler.Type == SamplerRateLimited || sampler.Type == SamplerParentBasedRateLimited
+	switch {
+	case argOk:
+		value, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid OTEL_TRACES_SAMPLER_ARG: %w", err)
+		}
+		if rateLimited {
+			sampler.TracesPerSecond = value
+		} else {
+			sampler.Ratio = &value
+		}
+	case !rateLimited:
+		// the ratio defaults to 1 when the sampler is set without argument.
+		ratio := 1.0
+		sampler.Ratio = &ratio
+	}
+	trace.Sampler = &sampler
+	return nil
+}
+
+func withEnvDetector(cfg *ResourceConfig) *ResourceConfig {
+	var out ResourceConfig
+	if cfg != nil {
+		out = *cfg
+	}
+	for _, detector := range out.Detectors {
+		if detector == "env" {
+			return &out
+		}
+	}
+	// the detectors applied last win, the env one is first for the
+	// attributes of the configuration to override it.
+	out.Detectors = append([]string{"env"}, out.Detectors...)
+	return &out
+}
+
+// otlpEndpoint sets the host, port, path and insecure keys from the URL
+// of OTEL_EXPORTER_OTLP_ENDPOINT.
+func otlpEndpoint(cfg map[string]interface{}, value string) error {
+	endpoint, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	switch endpoint.Scheme {
+	case "http":
+		cfg["insecure"] = true
+	case "https":
+		cfg["insecure"] = false
+	default:
+		return fmt.Errorf("unsupported scheme %q", endpoint.Scheme)
+	}
+	cfg["host"] = endpoint.Hostname()
+	if port := endpoint.Port(); port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return err
+		}
+		cfg["port"] = p
+	}
+	if endpoint.Path != "" && endpoint.Path != "/" {
+		cfg["path"] = endpoint.Path
+	}
+	return nil
+}
+
+// otlpHeaders parses the comma separated list of key=value pairs of
+// OTEL_EXPORTER_OTLP_HEADERS, the values being URL encoded.
+func otlpHeaders(cfg map[string]interface{}, value string) error {
+	headers := map[string]string{}
+	switch existing := cfg["headers"].(type) {
+	case map[string]string:
+		for k, v := range existing {
+			headers[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range existing {
+			headers[k] = fmt.Sprint(v)
+		}
+	}
+	for _, pair := range splitList(value) {
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid header %q", pair)
+		}
+		decoded, err := url.QueryUnescape(strings.TrimSpace(val))
+		if err != nil {
+			return err
+		}
+		headers[strings.TrimSpace(key)] = decoded
+	}
+	cfg["headers"] = headers
+	return nil
+}
+
+func stringValue(key string) func(map[string]interface{}, string) error {
+	return func(cfg map[string]interface{}, value string) error {
+		cfg[key] = value
+		return nil
+	}
+}
+
+func boolValue(key string) func(map[string]interface{}, string) error {
+	return func(cfg map[string]interface{}, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		cfg[key] = b
+		return nil
+	}
+}
+
+func intValue(key string) func(map[string]interface{}, string) error {
+	return func(cfg map[string]interface{}, value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		cfg[key] = i
+		return nil
+	}
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
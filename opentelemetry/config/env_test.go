@@ -0,0 +1,144 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "checkout")
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	t.Setenv("OTEL_LOGS_EXPORTER", "none")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "https://collector.example.com:4318/otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "api-key=secret%3D1, tenant = payments")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PORT", "9464")
+	t.Setenv("OTEL_TRACES_SAMPLER", "parentbased_traceidratio")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "0.25")
+	t.Setenv("OTEL_PROPAGATORS", "tracecontext,baggage,b3")
+
+	cfg, err := FromEnv()
+	require.NoError(t, err)
+
+	ratio := 0.25
+	require.Equal(t, &Config{
+		ServiceName: "checkout",
+		Exporters: []Exporter{
+			{
+				Name: "otlp",
+				Kind: "opentelemetry",
+				Config: map[string]interface{}{
+					"host":     "collector.example.com",
+					"port":     4318,
+					"path":     "/otlp",
+					"insecure": false,
+					"protocol": "http/protobuf",
+					"headers":  map[string]string{"api-key": "secret=1", "tenant": "payments"},
+				},
+			},
+			{
+				Name:   "prometheus",
+				Kind:   "prometheus",
+				Config: map[string]interface{}{"port": 9464},
+			},
+		},
+		Metrics: &MetricsConfig{Exporters: []string{"prometheus"}},
+		Trace: &TraceConfig{
+			Exporters:  []string{"otlp"},
+			SampleRate: 1,
+			Sampler:    &SamplerConfig{Type: "parentbased_traceidratio", Ratio: &ratio},
+		},
+		Propagators: []string{"tracecontext", "baggage", "b3"},
+	}, cfg)
+}
+
+func TestFromEnvConsole(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "console")
+	t.Setenv("OTEL_METRICS_EXPORTER", "console")
+	t.Setenv("OTEL_LOGS_EXPORTER", "console")
+
+	cfg, err := FromEnv()
+	require.NoError(t, err)
+	require.Equal(t, []Exporter{{Name: "console", Kind: "console", Config: map[string]interface{}{}}}, cfg.Exporters)
+	require.Equal(t, []string{"console"}, cfg.Trace.Exporters)
+	require.Equal(t, &MetricsConfig{Exporters: []string{"console"}}, cfg.Metrics)
+	require.Equal(t, &LogsConfig{Exporters: []string{"console"}}, cfg.Logs)
+}
+
+func TestFromEnvZipkin(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "zipkin")
+	t.Setenv("OTEL_METRICS_EXPORTER", "none")
+	t.Setenv("OTEL_LOGS_EXPORTER", "none")
+	t.Setenv("OTEL_EXPORTER_ZIPKIN_ENDPOINT", "http://zipkin:9411/api/v2/spans")
+	t.Setenv("OTEL_EXPORTER_ZIPKIN_TIMEOUT", "5000")
+
+	cfg, err := FromEnv()
+	require.NoError(t, err)
+	require.Equal(t, []Exporter{{
+		Name:   "zipkin",
+		Kind:   "zipkin",
+		Config: map[string]interface{}{"endpoint": "http://zipkin:9411/api/v2/spans", "timeout_ms": 5000},
+	}}, cfg.Exporters)
+	require.Equal(t, []string{"zipkin"}, cfg.Trace.Exporters)
+	require.Nil(t, cfg.Metrics)
+	require.Nil(t, cfg.Logs)
+}
+
+func TestWithEnv(t *testing.T) {
+	cfg := &Config{
+		ServiceName: "checkout",
+		Exporters: []Exporter{
+			{Name: "otlp", Kind: "opentelemetry", Config: map[string]interface{}{"host": "localhost", "port": 4317}},
+			{Name: "prom", Kind: "prometheus"},
+		},
+		Metrics: &MetricsConfig{Exporters: []string{"prom"}},
+		Trace:   &TraceConfig{Exporters: []string{"otlp"}, SampleRate: 0.1},
+	}
+	t.Setenv("OTEL_SERVICE_NAME", "checkout-canary")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://otel-agent:4317")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "0.5")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "team=payments")
+
+	out, err := WithEnv(cfg)
+	require.NoError(t, err)
+	require.Equal(t, "checkout-canary", out.ServiceName)
+	require.Equal(t, map[string]interface{}{"host": "otel-agent", "port": 4317, "insecure": true}, out.Exporters[0].Config)
+	require.Equal(t, []string{"prom"}, out.Metrics.Exporters)
+	require.Equal(t, 0.5, out.Trace.SampleRate)
+	require.Equal(t, []string{"env"}, out.Resource.Detectors)
+
+	// cfg is left untouched
+	require.Equal(t, "checkout", cfg.ServiceName)
+	require.Equal(t, map[string]interface{}{"host": "localhost", "port": 4317}, cfg.Exporters[0].Config)
+	require.Equal(t, 0.1, cfg.Trace.SampleRate)
+	require.Nil(t, cfg.Resource)
+}
+
+func TestWithEnvDisabled(t *testing.T) {
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	out, err := WithEnv(&Config{})
+	require.NoError(t, err)
+	require.True(t, out.Disabled)
+}
+
+func TestWithEnvErrors(t *testing.T) {
+	tests := map[string]struct {
+		variable string
+		value    string
+		err      string
+	}{
+		"unsupported exporter": {"OTEL_TRACES_EXPORTER", "prometheus", `invalid OTEL_TRACES_EXPORTER: unsupported exporter "prometheus"`},
+		"invalid endpoint":     {"OTEL_EXPORTER_OTLP_ENDPOINT", "collector:4317", `invalid OTEL_EXPORTER_OTLP_ENDPOINT: unsupported scheme "collector"`},
+		"invalid header":       {"OTEL_EXPORTER_OTLP_HEADERS", "api-key", `invalid OTEL_EXPORTER_OTLP_HEADERS: invalid header "api-key"`},
+		"invalid interval":     {"OTEL_METRIC_EXPORT_INTERVAL", "1m", `invalid OTEL_METRIC_EXPORT_INTERVAL: strconv.Atoi: parsing "1m": invalid syntax`},
+		"invalid disabled":     {"OTEL_SDK_DISABLED", "nope", `invalid OTEL_SDK_DISABLED: strconv.ParseBool: parsing "nope": invalid syntax`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(test.variable, test.value)
+			_, err := FromEnv()
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Command schemagen writes the JSON Schema of the telemetry configuration.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+)
+
+func main() {
+	out := flag.String("out", "config.schema.json", "path of the generated schema")
+	flag.Parse()
+
+	schema, err := config.JSONSchema()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = os.WriteFile(*out, append(schema, '\n'), 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the Config of a YAML (.yaml, .yml) or JSON (.json) file. The
+// unknown fields are rejected and the errors carry the path of the file and
+// the line of the problem. The settings of the exporters are checked when
+// they are created.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg *Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		cfg, err = decodeYAML(data)
+	case ".json":
+		cfg, err = decodeJSON(data)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config file extension %q", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func decodeYAML(data []byte) (*Config, error) {
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&cfg)
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("empty config")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// decodeJSON checks the syntax of data before decoding it as YAML, of which
+// JSON is a subset, as the YAML parser reports the JSON syntax errors poorly.
+func decodeJSON(data []byte) (*Config, error) {
+	var raw interface{}
+	err := json.Unmarshal(data, &raw)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// the offset is the one of the byte after the invalid one.
+		line, column := position(data, syntaxErr.Offset-1)
+		return nil, fmt.Errorf("line %d, column %d: %w", line, column, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeYAML(data)
+}
+
+// position returns the line and column of the byte at offset.
+func position(data []byte, offset int64) (int, int) {
+	offset = max(0, min(offset, int64(len(data))))
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
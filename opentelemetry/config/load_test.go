@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const yamlConfig = `service_name: checkout
+exporters:
+  - name: otel
+    kind: opentelemetry
+    config:
+      host: otel-collector
+      port: 4317
+      headers:
+        api-key: ${env:API_KEY}
+metrics:
+  exporters: [otel]
+traces:
+  exporters: [otel]
+  sample_rate: 0.5
+  sampler:
+    type: parentbased_traceidratio
+    rules:
+      - http_route: /health*
+        ratio: 0
+propagators: [tracecontext, baggage]
+`
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "telemetry.yaml", yamlConfig))
+	require.NoError(t, err)
+
+	zero := 0.0
+	expected := &Config{
+		ServiceName: "checkout",
+		Exporters: []Exporter{
+			{
+				Name: "otel",
+				Kind: "opentelemetry",
+				Config: map[string]interface{}{
+					"host":    "otel-collector",
+					"port":    4317,
+					"headers": map[string]interface{}{"api-key": "${env:API_KEY}"},
+				},
+			},
+		},
+		Metrics: &MetricsConfig{Exporters: []string{"otel"}},
+		Trace: &TraceConfig{
+			Exporters:  []string{"otel"},
+			SampleRate: 0.5,
+			Sampler: &SamplerConfig{
+				Type:  "parentbased_traceidratio",
+				Rules: []SamplingRule{{HTTPRoute: "/health*", Ratio: &zero}},
+			},
+		},
+		Propagators: []string{"tracecontext", "baggage"},
+	}
+	require.Equal(t, expected, cfg)
+
+	cfg, err = Load(writeConfig(t, "telemetry.json", `{
+	"service_name": "checkout",
+	"exporters": [{"name": "otel", "kind": "opentelemetry", "config": {"host": "otel-collector", "port": 4317, "headers": {"api-key": "${env:API_KEY}"}}}],
+	"metrics": {"exporters": ["otel"]},
+	"traces": {
+		"exporters": ["otel"],
+		"sample_rate": 0.5,
+		"sampler": {"type": "parentbased_traceidratio", "rules": [{"http_route": "/health*", "ratio": 0}]}
+	},
+	"propagators": ["tracecontext", "baggage"]
+}`))
+	require.NoError(t, err)
+	require.Equal(t, expected, cfg)
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		content string
+		err     string
+	}{
+		"unknown yaml field": {
+			name:    "telemetry.yaml",
+			content: "service_name: checkout\ntraces:\n  exporters: [otel]\n  sample_ratio: 1\n",
+			err:     "yaml: unmarshal errors:\n  line 4: field sample_ratio not found in type config.TraceConfig",
+		},
+		"invalid yaml type": {
+			name:    "telemetry.yml",
+			content: "traces:\n  sample_rate: all\n",
+			err:     "yaml: unmarshal errors:\n  line 2: cannot unmarshal !!str `all` into float64",
+		},
+		"unknown json field": {
+			name:    "telemetry.json",
+			content: "{\n  \"service_name\": \"checkout\",\n  \"trace\": {}\n}",
+			err:     "yaml: unmarshal errors:\n  line 3: field trace not found in type config.Config",
+		},
+		"invalid json": {
+			name:    "telemetry.json",
+			content: "{\n  \"service_name\": \"checkout\",\n}",
+			err:     "line 3, column 1: invalid character '}' looking for beginning of object key string",
+		},
+		"empty": {
+			name: "telemetry.yaml",
+			err:  "empty config",
+		},
+		"unsupported extension": {
+			name: "telemetry.toml",
+			err:  `unsupported config file extension ".toml"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(t, test.name, test.content)
+			_, err := Load(path)
+			require.EqualError(t, err, path+": "+test.err)
+		})
+	}
+}
+
+func TestJSONSchemaIsUpToDate(t *testing.T) {
+	schema, err := JSONSchema()
+	require.NoError(t, err)
+	published, err := os.ReadFile("config.schema.json")
+	require.NoError(t, err)
+	require.Equal(t, string(published), string(schema)+"\n", "run go generate ./config")
+}
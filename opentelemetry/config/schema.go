@@ -0,0 +1,92 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//go:generate go run ./internal/schemagen -out config.schema.json
+
+// SchemaID is the identifier of the JSON Schema of the Config.
+const SchemaID = "https://github.com/razorpay/golib/opentelemetry/config/config.schema.json"
+
+// JSONSchema returns the JSON Schema of the Config, generated from its Go
+// type, to lint the telemetry configuration files. The settings of the
+// exporters are only described as objects as they depend on their kind.
+func JSONSchema() ([]byte, error) {
+	defs := map[string]interface{}{}
+	root, err := typeSchema(reflect.TypeOf(Config{}), defs)
+	if err != nil {
+		return nil, err
+	}
+	schema := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     SchemaID,
+		"title":   "Telemetry configuration",
+		"$ref":    root["$ref"],
+		"$defs":   defs,
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema returns the schema of t, the structs being added to defs and
+// referenced by name.
+func typeSchema(t reflect.Type, defs map[string]interface{}) (map[string]interface{}, error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), defs)
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Slice:
+		items, err := typeSchema(t.Elem(), defs)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case reflect.Map:
+		values, err := typeSchema(t.Elem(), defs)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+		if _, ok := defs[t.Name()]; ok {
+			return ref, nil
+		}
+		// registered before the fields for the recursive types.
+		defs[t.Name()] = nil
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				return nil, fmt.Errorf("field %s.%s has no json name", t.Name(), field.Name)
+			}
+			property, err := typeSchema(field.Type, defs)
+			if err != nil {
+				return nil, err
+			}
+			properties[name] = property
+		}
+		defs[t.Name()] = map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		return ref, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 )
 
+// Parse decodes the settings of an exporter into cfgStructPointer, the
+// fields missing from configInString keeping their value.
 func Parse(configInString map[string]interface{}, cfgStructPointer interface{}) error {
 	if configInString == nil {
 		return nil
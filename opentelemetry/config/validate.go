@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var (
+	ErrEmptyName            = errors.New("name is empty")
+	ErrDuplicateName        = errors.New("name is declared more than once")
+	ErrUnknownExporter      = errors.New("exporter is not declared")
+	ErrUnsupportedSignal    = errors.New("exporter does not support the signal")
+	ErrNoSupportingExporter = errors.New("no declared exporter supports the signal")
+	ErrRatioOutOfRange      = errors.New("must be between 0 and 1")
+	ErrNegative             = errors.New("must not be negative")
+	ErrNotPositive          = errors.New("must be greater than 0")
+	ErrPortOutOfRange       = errors.New("must be a port between 0 and 65535")
+	ErrBatchLargerQueue     = errors.New("must not be larger than max_queue_size")
+)
+
+// ValidationError is a problem of the field of a Config at Field, the path
+// of the field with the keys of the configuration files (exp: traces.exporters[0]).
+type ValidationError struct {
+	Field string
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks cfg and returns all its problems at once, joined, each
+// being a *ValidationError. The settings specific to an exporter kind are
+// checked when the exporter is created, except the port which must be in
+// range for every kind.
+func Validate(cfg *Config) error {
+	if cfg == nil {
+		return &ValidationError{Field: "config", Err: ErrTelemetryConfigMissing}
+	}
+	v := &validator{}
+	if len(cfg.Exporters) == 0 {
+		v.add("exporters", ErrNoExporter)
+	}
+	if cfg.Metrics == nil && cfg.Trace == nil && cfg.Logs == nil {
+		v.add("config", ErrTelemetryConfigMissing)
+	}
+
+	declared := make(map[string]bool, len(cfg.Exporters))
+	for i, exporter := range cfg.Exporters {
+		field := fmt.Sprintf("exporters[%d]", i)
+		switch {
+		case exporter.Name == "":
+			v.add(field+".name", ErrEmptyName)
+		case declared[exporter.Name]:
+			v.add(field+".name", fmt.Errorf("%w: %s", ErrDuplicateName, exporter.Name))
+		}
+		declared[exporter.Name] = true
+		if exporter.Kind == "" {
+			v.add(field+".kind", ErrEmptyName)
+		}
+		if port, ok := exporter.Config["port"]; ok {
+			v.port(field+".config.port", port)
+		}
+	}
+
+	if cfg.Metrics != nil {
+		v.references("metrics.exporters", cfg.Metrics.Exporters, declared)
+	}
+	if cfg.Logs != nil {
+		v.references("logs.exporters", cfg.Logs.Exporters, declared)
+	}
+	if cfg.Trace != nil {
+		v.trace(cfg.Trace, declared)
+	}
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) add(field string, err error) {
+	v.errs = append(v.errs, &ValidationError{Field: field, Err: err})
+}
+
+func (v *validator) references(field string, names []string, declared map[string]bool) {
+	for i, name := range names {
+		if !declared[name] {
+			v.add(fmt.Sprintf("%s[%d]", field, i), fmt.Errorf("%w: %s", ErrUnknownExporter, name))
+		}
+	}
+}
+
+func (v *validator) ratio(field string, ratio float64) {
+	if math.IsNaN(ratio) || ratio < 0 || ratio > 1 {
+		v.add(field, ErrRatioOutOfRange)
+	}
+}
+
+func (v *validator) nonNegative(field string, value float64) {
+	if value < 0 {
+		v.add(field, ErrNegative)
+	}
+}
+
+func (v *validator) positive(field string, value float64) {
+	if !(value > 0) {
+		v.add(field, ErrNotPositive)
+	}
+}
+
+// port checks a port of the settings of an exporter, an integer of any
+// type depending on how they were decoded.
+func (v *validator) port(field string, port interface{}) {
+	var value float64
+	switch p := port.(type) {
+	case int:
+		value = float64(p)
+	case int64:
+		value = float64(p)
+	case uint64:
+		value = float64(p)
+	case float64:
+		value = p
+	default:
+		v.add(field, ErrPortOutOfRange)
+		return
+	}
+	if value != math.Trunc(value) || value < 0 || value > math.MaxUint16 {
+		v.add(field, ErrPortOutOfRange)
+	}
+}
+
+func (v *validator) trace(cfg *TraceConfig, declared map[string]bool) {
+	v.references("traces.exporters", cfg.Exporters, declared)
+	v.ratio("traces.sample_rate", cfg.SampleRate)
+	if cfg.Sampler != nil {
+		if cfg.Sampler.Ratio != nil {
+			v.ratio("traces.sampler.ratio", *cfg.Sampler.Ratio)
+		}
+		switch cfg.Sampler.Type {
+		case SamplerRateLimited, SamplerParentBasedRateLimited:
+			// no trace would be sampled without a budget.
+			v.positive("traces.sampler.traces_per_second", cfg.Sampler.TracesPerSecond)
+		default:
+			v.nonNegative("traces.sampler.traces_per_second", cfg.Sampler.TracesPerSecond)
+		}
+		for i, rule := range cfg.Sampler.Rules {
+			if rule.Ratio != nil {
+				v.ratio(fmt.Sprintf("traces.sampler.rules[%d].ratio", i), *rule.Ratio)
+			}
+		}
+	}
+	names := make([]string, 0, len(cfg.Processors))
+	for name := range cfg.Processors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		processor := cfg.Processors[name]
+		field := "traces.processors." + name
+		if !declared[name] {
+			v.add(field, fmt.Errorf("%w: %s", ErrUnknownExporter, name))
+		}
+		v.nonNegative(field+".max_queue_size", float64(processor.MaxQueueSize))
+		v.nonNegative(field+".max_export_batch_size", float64(processor.MaxExportBatchSize))
+		v.nonNegative(field+".batch_timeout_ms", float64(processor.BatchTimeoutMs))
+		v.nonNegative(field+".export_timeout_ms", float64(processor.ExportTimeoutMs))
+		if processor.MaxQueueSize > 0 && processor.MaxExportBatchSize > processor.MaxQueueSize {
+			v.add(field+".max_export_batch_size", ErrBatchLargerQueue)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	ratio := 0.5
+	cfg := &Config{
+		ServiceName: "checkout",
+		Exporters: []Exporter{
+			{Name: "otel", Kind: "opentelemetry", Config: map[string]interface{}{"port": 4317}},
+			{Name: "prom", Kind: "prometheus", Config: map[string]interface{}{"port": float64(9090)}},
+		},
+		Metrics: &MetricsConfig{Exporters: []string{"prom"}},
+		Trace: &TraceConfig{
+			Exporters:  []string{"otel"},
+			SampleRate: 1,
+			Sampler:    &SamplerConfig{Type: "parentbased_traceidratio", Ratio: &ratio},
+			Processors: map[string]SpanProcessorConfig{"otel": {MaxQueueSize: 4096, MaxExportBatchSize: 1024}},
+		},
+	}
+	require.NoError(t, Validate(cfg))
+}
+
+func TestValidateErrors(t *testing.T) {
+	ratio := 1.5
+	cfg := &Config{
+		Exporters: []Exporter{
+			{Name: "otel", Kind: "opentelemetry", Config: map[string]interface{}{"port": 70000}},
+			{Name: "otel", Kind: "prometheus"},
+			{Kind: "prometheus", Config: map[string]interface{}{"port": "9090"}},
+		},
+		Metrics: &MetricsConfig{Exporters: []string{"prom"}},
+		Trace: &TraceConfig{
+			Exporters:  []string{"otel"},
+			SampleRate: -1,
+			Sampler:    &SamplerConfig{Rules: []SamplingRule{{SpanName: "GET *", Ratio: &ratio}}},
+			Processors: map[string]SpanProcessorConfig{"otel": {MaxQueueSize: 10, MaxExportBatchSize: 20}},
+		},
+	}
+	err := Validate(cfg)
+	require.EqualError(t, err, `exporters[0].config.port: must be a port between 0 and 65535
+exporters[1].name: name is declared more than once: otel
+exporters[2].name: name is empty
+exporters[2].config.port: must be a port between 0 and 65535
+metrics.exporters[0]: exporter is not declared: prom
+traces.sample_rate: must be between 0 and 1
+traces.sampler.rules[0].ratio: must be between 0 and 1
+traces.processors.otel.max_export_batch_size: must not be larger than max_queue_size`)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "exporters[0].config.port", validationErr.Field)
+	require.ErrorIs(t, err, ErrUnknownExporter)
+
+	var fields []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		require.True(t, errors.As(e, &validationErr))
+		fields = append(fields, validationErr.Field)
+	}
+	require.Len(t, fields, 8)
+}
+
+func TestValidateRateLimitedSampler(t *testing.T) {
+	for _, samplerType := range []string{"ratelimited", "parentbased_ratelimited"} {
+		cfg := &Config{
+			Exporters: []Exporter{{Name: "otel", Kind: "opentelemetry"}},
+			Trace: &TraceConfig{
+				Exporters: []string{"otel"},
+				Sampler:   &SamplerConfig{Type: samplerType},
+			},
+		}
+		require.EqualError(t, Validate(cfg), "traces.sampler.traces_per_second: must be greater than 0")
+		cfg.Trace.Sampler.TracesPerSecond = 10
+		require.NoError(t, Validate(cfg))
+	}
+}
+
+func TestValidateMissing(t *testing.T) {
+	require.ErrorIs(t, Validate(nil), ErrTelemetryConfigMissing)
+
+	err := Validate(&Config{})
+	require.ErrorIs(t, err, ErrNoExporter)
+	require.ErrorIs(t, err, ErrTelemetryConfigMissing)
+}
@@ -18,13 +18,17 @@ import (
 
 func main() {
 	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
-	setupObs(ctx)
+	telemetry := setupObs(ctx)
 	instrumentRandomData(ctx)
-	// Wait for flushing telemetry data via exporters
+	// Leave some time to prometheus to scrape the metrics endpoint
 	<-time.After(5 * time.Second)
-	// After cancel traceprovider, meterprovider and metrics endpoint would shut down
-	cancel()
+	// Shutdown flushes the pending telemetry data, then stops the traceprovider,
+	// meterprovider and metrics endpoint
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := telemetry.Shutdown(ctx); err != nil {
+		panic(err)
+	}
 }
 
 func instrumentRandomData(ctx context.Context) {
@@ -69,7 +73,7 @@ func instrumentRandomData(ctx context.Context) {
 	histogram.Record(ctx, 830, opt)
 }
 
-func setupObs(ctx context.Context) {
+func setupObs(ctx context.Context) *gotel.Telemetry {
 	cfg := &config.Config{
 		ServiceName: "test-service",
 		Exporters: []config.Exporter{
@@ -88,7 +92,7 @@ func setupObs(ctx context.Context) {
 				Config: map[string]interface{}{
 					"host":     "localhost",
 					"port":     4317,
-					"use_http": false,
+					"protocol": "grpc",
 				},
 			},
 		},
@@ -100,8 +104,9 @@ func setupObs(ctx context.Context) {
 			SampleRate: 1.0,
 		},
 	}
-	err := gotel.Register(ctx, cfg, nil)
+	telemetry, err := gotel.Setup(ctx, cfg, nil)
 	if err != nil {
 		panic(err)
 	}
+	return telemetry
 }
@@ -0,0 +1,146 @@
+// Package console implements an exporter printing the telemetry data to
+// the standard output or error, for the local development.
+package console
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/periodic"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// ExporterKey is the name for the console exporter
+	ExporterKey = config.ExporterKind("console")
+
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	FormatText   = "text"
+	FormatJSON   = "json"
+
+	ExportIntervalMs = 10000
+)
+
+var ErrUnknownOutput = errors.New("output must be stdout or stderr")
+var ErrUnknownFormat = errors.New("format must be text or json")
+
+// CollectorConfig has the variables to configure the console exporter
+type CollectorConfig struct {
+	// Output is the stream the telemetry data is printed to: stdout or stderr
+	Output string `json:"output"`
+	// Format is the format of the telemetry data: text, indented for humans, or json, one object per line
+	Format string `json:"format"`
+	// ExportIntervalMs is the interval between two dumps of the metrics
+	ExportIntervalMs int `json:"export_interval_ms"`
+}
+
+// Collector implements the traces, metrics and logs exporter. The spans
+// are printed as a tree once the local root span of their trace ends.
+type Collector struct {
+	spanExporter *spanExporter
+	logExporter  *logExporter
+	reader       *periodic.LazyReader
+}
+
+// SpanExporter implements the interface to export traces.
+func (c *Collector) SpanExporter() sdktrace.SpanExporter {
+	return c.spanExporter
+}
+
+// MetricReader implements the interface to export metrics, printed
+// periodically.
+func (c *Collector) MetricReader() sdkmetric.Reader {
+	return c.reader.Reader()
+}
+
+// LogExporter implements the interface to export logs.
+func (c *Collector) LogExporter() sdklog.Exporter {
+	return c.logExporter
+}
+
+// Shutdown prints the spans of the traces whose root span did not end.
+func (c *Collector) Shutdown(ctx context.Context) error {
+	return c.spanExporter.Shutdown(ctx)
+}
+
+// ParseConfig creates a console exporter configuration.
+func ParseConfig(in map[string]interface{}) (*CollectorConfig, error) {
+	defaultConfig := CollectorConfig{
+		Output:           OutputStdout,
+		Format:           FormatText,
+		ExportIntervalMs: ExportIntervalMs,
+	}
+	err := config.Parse(in, &defaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &defaultConfig, nil
+}
+
+// CreateExporter creates a console exporter instance.
+func CreateExporter(_ context.Context, cfg map[string]interface{}) (interface{}, error) {
+	consoleCfg, err := ParseConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var out io.Writer
+	switch consoleCfg.Output {
+	case OutputStdout:
+		out = os.Stdout
+	case OutputStderr:
+		out = os.Stderr
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOutput, consoleCfg.Output)
+	}
+	if consoleCfg.Format != FormatText && consoleCfg.Format != FormatJSON {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, consoleCfg.Format)
+	}
+	return newCollector(out, consoleCfg), nil
+}
+
+func newCollector(out io.Writer, cfg *CollectorConfig) *Collector {
+	p := &printer{out: out, json: cfg.Format == FormatJSON}
+	return &Collector{
+		spanExporter: newSpanExporter(p),
+		logExporter:  &logExporter{printer: p},
+		reader: periodic.NewLazyReader(&metricExporter{printer: p},
+			sdkmetric.WithInterval(time.Duration(cfg.ExportIntervalMs)*time.Millisecond),
+		),
+	}
+}
+
+// printer writes the telemetry data of all the signals, a block at a time.
+type printer struct {
+	mu   sync.Mutex
+	out  io.Writer
+	json bool
+}
+
+// print writes the block built by text, or value as a JSON line.
+func (p *printer) print(text func(b *bytes.Buffer), value interface{}) error {
+	var b bytes.Buffer
+	if p.json {
+		err := json.NewEncoder(&b).Encode(value)
+		if err != nil {
+			return err
+		}
+	} else {
+		text(&b)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.out.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,148 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig(map[string]interface{}{"format": "json"})
+	require.NoError(t, err)
+	require.Equal(t, &CollectorConfig{Output: OutputStdout, Format: FormatJSON, ExportIntervalMs: ExportIntervalMs}, cfg)
+
+	_, err = CreateExporter(context.Background(), map[string]interface{}{"output": "file"})
+	require.ErrorIs(t, err, ErrUnknownOutput)
+	_, err = CreateExporter(context.Background(), map[string]interface{}{"format": "yaml"})
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestTraceTree(t *testing.T) {
+	ctx := context.Background()
+	var out bytes.Buffer
+	collector := newCollector(&out, &CollectorConfig{Format: FormatText})
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(collector.SpanExporter()),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName("checkout"))),
+	)
+	tracer := tracerProvider.Tracer("test")
+
+	ctx, root := tracer.Start(ctx, "GET /orders", trace.WithSpanKind(trace.SpanKindServer))
+	root.SetAttributes(attribute.String("http.route", "/orders"))
+	childCtx, child := tracer.Start(ctx, "load", trace.WithAttributes(attribute.Int("count", 2)))
+	_, grandchild := tracer.Start(childCtx, "SELECT orders", trace.WithSpanKind(trace.SpanKindClient))
+	grandchild.AddEvent("retry")
+	grandchild.SetStatus(codes.Error, "timeout")
+	grandchild.End()
+	child.End()
+	require.Empty(t, out.String(), "the trace is printed once its root span ends")
+	root.End()
+
+	require.Regexp(t, regexp.MustCompile(`^trace [0-9a-f]{32} \(checkout\)
+  GET /orders \[server\] \S+ \{http.route=/orders\}
+    load \[internal\] \S+ \{count=2\}
+      SELECT orders \[client\] \S+ Error: timeout
+        \* retry
+$`), out.String())
+}
+
+func TestTraceJSON(t *testing.T) {
+	ctx := context.Background()
+	var out bytes.Buffer
+	collector := newCollector(&out, &CollectorConfig{Format: FormatJSON})
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(collector.SpanExporter()))
+	tracer := tracerProvider.Tracer("test")
+
+	ctx, root := tracer.Start(ctx, "root")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	_, orphan := tracer.Start(ctx, "orphan")
+	root.End()
+	orphan.End()
+
+	// the span ending after its root is printed without waiting for the shutdown
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	printed := out.Len()
+	require.NoError(t, collector.Shutdown(ctx))
+	require.Equal(t, printed, out.Len())
+	var complete, partial jsonTrace
+	require.NoError(t, json.Unmarshal(lines[0], &complete))
+	require.NoError(t, json.Unmarshal(lines[1], &partial))
+	require.Equal(t, root.SpanContext().TraceID().String(), complete.TraceID)
+	require.Len(t, complete.Spans, 1)
+	require.Equal(t, "root", complete.Spans[0].Name)
+	require.Len(t, complete.Spans[0].Children, 1)
+	require.Equal(t, "child", complete.Spans[0].Children[0].Name)
+	require.Len(t, partial.Spans, 1)
+	require.Equal(t, "orphan", partial.Spans[0].Name)
+}
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		format string
+		want   *regexp.Regexp
+	}{
+		{
+			format: FormatText,
+			want: regexp.MustCompile(`^metrics \S+
+  requests\{route=/orders\} 3
+  latency count=2 sum=4 min=1 max=3 \(ms\)
+$`),
+		},
+		{
+			format: FormatJSON,
+			want:   regexp.MustCompile(`^\{"time":"[^"]+","metrics":\[\{"scope":"test","name":"requests","type":"sum","points":\[\{"attributes":\{"route":"/orders"\},"value":3\}\]\},\{"scope":"test","name":"latency","unit":"ms","type":"histogram","points":\[\{"count":2,"sum":4,"min":1,"max":3,"bounds":\[[0-9,]+\],"bucket_counts":\[[0-9,]+\]\}\]\}\]\}\n$`),
+		},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			var out bytes.Buffer
+			collector := newCollector(&out, &CollectorConfig{Format: tc.format, ExportIntervalMs: ExportIntervalMs})
+			meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(collector.MetricReader()))
+			meter := meterProvider.Meter("test")
+			counter, err := meter.Int64Counter("requests")
+			require.NoError(t, err)
+			counter.Add(ctx, 3, otelmetric.WithAttributes(attribute.String("route", "/orders")))
+			histogram, err := meter.Float64Histogram("latency", otelmetric.WithUnit("ms"))
+			require.NoError(t, err)
+			histogram.Record(ctx, 1)
+			histogram.Record(ctx, 3)
+
+			require.NoError(t, meterProvider.ForceFlush(ctx))
+			require.Regexp(t, tc.want, out.String())
+			require.NoError(t, meterProvider.Shutdown(ctx))
+			require.NoError(t, collector.Shutdown(ctx))
+		})
+	}
+}
+
+func TestLogs(t *testing.T) {
+	ctx := context.Background()
+	var out bytes.Buffer
+	collector := newCollector(&out, &CollectorConfig{Format: FormatText})
+	loggerProvider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(collector.LogExporter())))
+
+	var record log.Record
+	record.SetSeverity(log.SeverityWarn)
+	record.SetBody(log.StringValue("payment declined"))
+	record.AddAttributes(log.Int("amount", 12))
+	loggerProvider.Logger("test").Emit(ctx, record)
+
+	require.Regexp(t, regexp.MustCompile(`^\S+ WARN payment declined \{amount=12\}\n$`), out.String())
+}
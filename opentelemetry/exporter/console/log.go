@@ -0,0 +1,138 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// logExporter prints a log record per line.
+type logExporter struct {
+	printer *printer
+}
+
+func (e *logExporter) Export(_ context.Context, records []sdklog.Record) error {
+	var errs []error
+	for _, record := range records {
+		r := newLogRecord(record)
+		errs = append(errs, e.printer.print(r.text, r))
+	}
+	return errors.Join(errs...)
+}
+
+func (e *logExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *logExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+type jsonLogRecord struct {
+	Time       time.Time              `json:"time"`
+	Severity   string                 `json:"severity,omitempty"`
+	Body       interface{}            `json:"body,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	TraceID    string                 `json:"trace_id,omitempty"`
+	SpanID     string                 `json:"span_id,omitempty"`
+
+	body       log.Value
+	attributes []log.KeyValue
+}
+
+func newLogRecord(record sdklog.Record) jsonLogRecord {
+	r := jsonLogRecord{
+		Time:     record.Timestamp(),
+		Severity: record.SeverityText(),
+		Body:     logValue(record.Body()),
+		body:     record.Body(),
+	}
+	if r.Time.IsZero() {
+		r.Time = record.ObservedTimestamp()
+	}
+	if r.Severity == "" && record.Severity() != log.SeverityUndefined {
+		r.Severity = record.Severity().String()
+	}
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		r.attributes = append(r.attributes, kv)
+		return true
+	})
+	if len(r.attributes) > 0 {
+		r.Attributes = make(map[string]interface{}, len(r.attributes))
+		for _, kv := range r.attributes {
+			r.Attributes[kv.Key] = logValue(kv.Value)
+		}
+	}
+	if record.TraceID().IsValid() {
+		r.TraceID = record.TraceID().String()
+	}
+	if record.SpanID().IsValid() {
+		r.SpanID = record.SpanID().String()
+	}
+	return r
+}
+
+// text prints the record on a line:
+//
+//	2024-01-02T15:04:05Z INFO payment captured {amount=12} trace_id=4bf9... span_id=00f0...
+func (r jsonLogRecord) text(b *bytes.Buffer) {
+	b.WriteString(r.Time.Format(time.RFC3339Nano))
+	if r.Severity != "" {
+		fmt.Fprintf(b, " %s", r.Severity)
+	}
+	if r.body.Kind() != log.KindEmpty {
+		fmt.Fprintf(b, " %s", r.body)
+	}
+	if len(r.attributes) > 0 {
+		b.WriteString(" {")
+		for i, kv := range r.attributes {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(b, "%s=%s", kv.Key, kv.Value)
+		}
+		b.WriteByte('}')
+	}
+	if r.TraceID != "" {
+		fmt.Fprintf(b, " trace_id=%s span_id=%s", r.TraceID, r.SpanID)
+	}
+	b.WriteByte('\n')
+}
+
+// logValue converts v to the JSON representation of its kind.
+func logValue(v log.Value) interface{} {
+	switch v.Kind() {
+	case log.KindBool:
+		return v.AsBool()
+	case log.KindFloat64:
+		return v.AsFloat64()
+	case log.KindInt64:
+		return v.AsInt64()
+	case log.KindString:
+		return v.AsString()
+	case log.KindBytes:
+		return base64.StdEncoding.EncodeToString(v.AsBytes())
+	case log.KindSlice:
+		values := v.AsSlice()
+		out := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			out = append(out, logValue(value))
+		}
+		return out
+	case log.KindMap:
+		kvs := v.AsMap()
+		out := make(map[string]interface{}, len(kvs))
+		for _, kv := range kvs {
+			out[kv.Key] = logValue(kv.Value)
+		}
+		return out
+	default:
+		return nil
+	}
+}
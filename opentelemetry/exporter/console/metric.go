@@ -0,0 +1,205 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// metricExporter prints all the data points of a collection at once.
+type metricExporter struct {
+	printer *printer
+}
+
+func (e *metricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(kind)
+}
+
+func (e *metricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e *metricExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	dump := jsonMetrics{Time: time.Now()}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metric := jsonMetric{Scope: scope.Scope.Name, Name: m.Name, Unit: m.Unit}
+			metric.Type, metric.Points = dataPoints(m.Data)
+			if len(metric.Points) > 0 {
+				dump.Metrics = append(dump.Metrics, metric)
+			}
+		}
+	}
+	if len(dump.Metrics) == 0 {
+		return nil
+	}
+	return e.printer.print(dump.text, dump)
+}
+
+func (e *metricExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *metricExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+type jsonMetrics struct {
+	Time    time.Time    `json:"time"`
+	Metrics []jsonMetric `json:"metrics"`
+}
+
+type jsonMetric struct {
+	Scope  string      `json:"scope"`
+	Name   string      `json:"name"`
+	Unit   string      `json:"unit,omitempty"`
+	Type   string      `json:"type"`
+	Points []jsonPoint `json:"points"`
+}
+
+type jsonPoint struct {
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Value        *float64               `json:"value,omitempty"`
+	Count        *uint64                `json:"count,omitempty"`
+	Sum          *float64               `json:"sum,omitempty"`
+	Min          *float64               `json:"min,omitempty"`
+	Max          *float64               `json:"max,omitempty"`
+	Bounds       []float64              `json:"bounds,omitempty"`
+	BucketCounts []uint64               `json:"bucket_counts,omitempty"`
+
+	attributes []attribute.KeyValue
+}
+
+// text prints a data point per line:
+//
+//	metrics 2024-01-02T15:04:05Z
+//	  http.server.requests{route=/orders} 12
+//	  http.server.duration{route=/orders} count=12 sum=1.5 min=0.01 max=0.6 (s)
+func (m jsonMetrics) text(b *bytes.Buffer) {
+	fmt.Fprintf(b, "metrics %s\n", m.Time.Format(time.RFC3339))
+	for _, metric := range m.Metrics {
+		unit := ""
+		if metric.Unit != "" {
+			unit = fmt.Sprintf(" (%s)", metric.Unit)
+		}
+		for _, point := range metric.Points {
+			fmt.Fprintf(b, "  %s%s %s%s\n", metric.Name, attributeText(point.attributes), point.text(), unit)
+		}
+	}
+}
+
+func (p jsonPoint) text() string {
+	if p.Value != nil {
+		return formatFloat(*p.Value)
+	}
+	fields := []string{fmt.Sprintf("count=%d", *p.Count)}
+	for _, field := range []struct {
+		name  string
+		value *float64
+	}{{"sum", p.Sum}, {"min", p.Min}, {"max", p.Max}} {
+		if field.value != nil {
+			fields = append(fields, fmt.Sprintf("%s=%s", field.name, formatFloat(*field.value)))
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// dataPoints returns the type and the data points of the aggregation.
+func dataPoints(data metricdata.Aggregation) (string, []jsonPoint) {
+	switch data := data.(type) {
+	case metricdata.Sum[int64]:
+		return "sum", valuePoints(data.DataPoints)
+	case metricdata.Sum[float64]:
+		return "sum", valuePoints(data.DataPoints)
+	case metricdata.Gauge[int64]:
+		return "gauge", valuePoints(data.DataPoints)
+	case metricdata.Gauge[float64]:
+		return "gauge", valuePoints(data.DataPoints)
+	case metricdata.Histogram[int64]:
+		return "histogram", histogramPoints(data.DataPoints)
+	case metricdata.Histogram[float64]:
+		return "histogram", histogramPoints(data.DataPoints)
+	case metricdata.ExponentialHistogram[int64]:
+		return "exponential_histogram", exponentialHistogramPoints(data.DataPoints)
+	case metricdata.ExponentialHistogram[float64]:
+		return "exponential_histogram", exponentialHistogramPoints(data.DataPoints)
+	case metricdata.Summary:
+		return "summary", summaryPoints(data.DataPoints)
+	default:
+		return fmt.Sprintf("%T", data), nil
+	}
+}
+
+func valuePoints[N int64 | float64](points []metricdata.DataPoint[N]) []jsonPoint {
+	out := make([]jsonPoint, 0, len(points))
+	for _, point := range points {
+		value := float64(point.Value)
+		out = append(out, newPoint(point.Attributes, jsonPoint{Value: &value}))
+	}
+	return out
+}
+
+func histogramPoints[N int64 | float64](points []metricdata.HistogramDataPoint[N]) []jsonPoint {
+	out := make([]jsonPoint, 0, len(points))
+	for _, point := range points {
+		count, sum := point.Count, float64(point.Sum)
+		out = append(out, newPoint(point.Attributes, jsonPoint{
+			Count:        &count,
+			Sum:          &sum,
+			Min:          extrema(point.Min),
+			Max:          extrema(point.Max),
+			Bounds:       point.Bounds,
+			BucketCounts: point.BucketCounts,
+		}))
+	}
+	return out
+}
+
+func exponentialHistogramPoints[N int64 | float64](points []metricdata.ExponentialHistogramDataPoint[N]) []jsonPoint {
+	out := make([]jsonPoint, 0, len(points))
+	for _, point := range points {
+		count, sum := point.Count, float64(point.Sum)
+		out = append(out, newPoint(point.Attributes, jsonPoint{
+			Count: &count,
+			Sum:   &sum,
+			Min:   extrema(point.Min),
+			Max:   extrema(point.Max),
+		}))
+	}
+	return out
+}
+
+func summaryPoints(points []metricdata.SummaryDataPoint) []jsonPoint {
+	out := make([]jsonPoint, 0, len(points))
+	for _, point := range points {
+		count, sum := point.Count, point.Sum
+		out = append(out, newPoint(point.Attributes, jsonPoint{Count: &count, Sum: &sum}))
+	}
+	return out
+}
+
+func newPoint(set attribute.Set, point jsonPoint) jsonPoint {
+	point.attributes = set.ToSlice()
+	point.Attributes = attributeMap(point.attributes)
+	return point
+}
+
+func extrema[N int64 | float64](e metricdata.Extrema[N]) *float64 {
+	value, ok := e.Value()
+	if !ok {
+		return nil
+	}
+	out := float64(value)
+	return &out
+}
@@ -0,0 +1,273 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxPendingTraces bounds the traces waiting for their root span, the
+// oldest one is printed incomplete beyond it.
+const maxPendingTraces = 1024
+
+// maxFlushedTraces bounds the printed traces remembered to print their late
+// spans right away, the spans of the older ones wait for a root again.
+const maxFlushedTraces = 1024
+
+// spanExporter buffers the spans of a trace until its local root span,
+// without parent or with a remote one, ends. The spans ending after it are
+// printed right away.
+type spanExporter struct {
+	printer *printer
+
+	mu           sync.Mutex
+	pending      map[trace.TraceID][]sdktrace.ReadOnlySpan
+	order        []trace.TraceID
+	flushed      map[trace.TraceID]bool
+	flushedOrder []trace.TraceID
+}
+
+func newSpanExporter(p *printer) *spanExporter {
+	return &spanExporter{
+		printer: p,
+		pending: map[trace.TraceID][]sdktrace.ReadOnlySpan{},
+		flushed: map[trace.TraceID]bool{},
+	}
+}
+
+func (e *spanExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var complete [][]sdktrace.ReadOnlySpan
+	late := map[trace.TraceID]int{}
+	e.mu.Lock()
+	for _, span := range spans {
+		id := span.SpanContext().TraceID()
+		if _, ok := e.pending[id]; !ok && e.flushed[id] {
+			if i, ok := late[id]; ok {
+				complete[i] = append(complete[i], span)
+			} else {
+				late[id] = len(complete)
+				complete = append(complete, []sdktrace.ReadOnlySpan{span})
+			}
+			continue
+		}
+		if _, ok := e.pending[id]; !ok {
+			e.order = append(e.order, id)
+		}
+		e.pending[id] = append(e.pending[id], span)
+		if !span.Parent().IsValid() || span.Parent().IsRemote() {
+			complete = append(complete, e.take(id))
+		}
+	}
+	for len(e.order) > maxPendingTraces {
+		complete = append(complete, e.take(e.order[0]))
+	}
+	e.mu.Unlock()
+	return e.print(complete)
+}
+
+// Shutdown prints the traces whose root span did not end.
+func (e *spanExporter) Shutdown(context.Context) error {
+	var complete [][]sdktrace.ReadOnlySpan
+	e.mu.Lock()
+	for len(e.order) > 0 {
+		complete = append(complete, e.take(e.order[0]))
+	}
+	e.mu.Unlock()
+	return e.print(complete)
+}
+
+// take removes the spans of the trace id from the pending ones and
+// remembers that the trace was printed.
+func (e *spanExporter) take(id trace.TraceID) []sdktrace.ReadOnlySpan {
+	spans := e.pending[id]
+	delete(e.pending, id)
+	for i, pending := range e.order {
+		if pending == id {
+			e.order = append(e.order[:i], e.order[i+1:]...)
+			break
+		}
+	}
+	if !e.flushed[id] {
+		e.flushed[id] = true
+		e.flushedOrder = append(e.flushedOrder, id)
+		if len(e.flushedOrder) > maxFlushedTraces {
+			delete(e.flushed, e.flushedOrder[0])
+			e.flushedOrder = e.flushedOrder[1:]
+		}
+	}
+	return spans
+}
+
+func (e *spanExporter) print(traces [][]sdktrace.ReadOnlySpan) error {
+	var errs []error
+	for _, spans := range traces {
+		t := newTrace(spans)
+		errs = append(errs, e.printer.print(t.text, t))
+	}
+	return errors.Join(errs...)
+}
+
+type jsonTrace struct {
+	TraceID string     `json:"trace_id"`
+	Service string     `json:"service,omitempty"`
+	Spans   []jsonSpan `json:"spans"`
+}
+
+type jsonSpan struct {
+	Name        string                 `json:"name"`
+	SpanID      string                 `json:"span_id"`
+	Kind        string                 `json:"kind"`
+	Start       time.Time              `json:"start"`
+	Duration    string                 `json:"duration"`
+	Status      string                 `json:"status,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Events      []jsonEvent            `json:"events,omitempty"`
+	Children    []jsonSpan             `json:"children,omitempty"`
+
+	attributes []attribute.KeyValue
+}
+
+type jsonEvent struct {
+	Name       string                 `json:"name"`
+	Time       time.Time              `json:"time"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	attributes []attribute.KeyValue
+}
+
+// newTrace builds the tree of the spans of a trace, the spans whose parent
+// is missing being roots, the siblings ordered by start time.
+func newTrace(spans []sdktrace.ReadOnlySpan) jsonTrace {
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].StartTime().Before(spans[j].StartTime())
+	})
+	ids := make(map[trace.SpanID]bool, len(spans))
+	for _, span := range spans {
+		ids[span.SpanContext().SpanID()] = true
+	}
+	children := map[trace.SpanID][]sdktrace.ReadOnlySpan{}
+	var roots []sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		parent := span.Parent().SpanID()
+		if span.Parent().IsValid() && ids[parent] {
+			children[parent] = append(children[parent], span)
+		} else {
+			roots = append(roots, span)
+		}
+	}
+
+	var build func(span sdktrace.ReadOnlySpan) jsonSpan
+	build = func(span sdktrace.ReadOnlySpan) jsonSpan {
+		out := jsonSpan{
+			Name:       span.Name(),
+			SpanID:     span.SpanContext().SpanID().String(),
+			Kind:       span.SpanKind().String(),
+			Start:      span.StartTime(),
+			Duration:   span.EndTime().Sub(span.StartTime()).Round(time.Microsecond).String(),
+			Attributes: attributeMap(span.Attributes()),
+			attributes: span.Attributes(),
+		}
+		if span.Status().Code != codes.Unset {
+			out.Status = span.Status().Code.String()
+			out.Description = span.Status().Description
+		}
+		for _, event := range span.Events() {
+			out.Events = append(out.Events, jsonEvent{
+				Name:       event.Name,
+				Time:       event.Time,
+				Attributes: attributeMap(event.Attributes),
+				attributes: event.Attributes,
+			})
+		}
+		for _, child := range children[span.SpanContext().SpanID()] {
+			out.Children = append(out.Children, build(child))
+		}
+		return out
+	}
+
+	t := jsonTrace{TraceID: spans[0].SpanContext().TraceID().String()}
+	if service, ok := spans[0].Resource().Set().Value(semconv.ServiceNameKey); ok {
+		t.Service = service.Emit()
+	}
+	for _, root := range roots {
+		t.Spans = append(t.Spans, build(root))
+	}
+	return t
+}
+
+// text prints the trace as a tree indented by depth:
+//
+//	trace 4bf92f3577b34da6a3ce929d0e0e4736 (checkout)
+//	  GET /orders [server] 12.3ms {http.route=/orders}
+//	    * cache miss {key=orders}
+//	    SELECT orders [client] 3.1ms Error: timeout
+func (t jsonTrace) text(b *bytes.Buffer) {
+	fmt.Fprintf(b, "trace %s", t.TraceID)
+	if t.Service != "" {
+		fmt.Fprintf(b, " (%s)", t.Service)
+	}
+	b.WriteByte('\n')
+	var write func(span jsonSpan, depth int)
+	write = func(span jsonSpan, depth int) {
+		indent := strings.Repeat("  ", depth)
+		fmt.Fprintf(b, "%s%s [%s] %s", indent, span.Name, span.Kind, span.Duration)
+		if len(span.attributes) > 0 {
+			fmt.Fprintf(b, " %s", attributeText(span.attributes))
+		}
+		if span.Status != "" {
+			fmt.Fprintf(b, " %s", span.Status)
+			if span.Description != "" {
+				fmt.Fprintf(b, ": %s", span.Description)
+			}
+		}
+		b.WriteByte('\n')
+		for _, event := range span.Events {
+			fmt.Fprintf(b, "%s  * %s", indent, event.Name)
+			if len(event.attributes) > 0 {
+				fmt.Fprintf(b, " %s", attributeText(event.attributes))
+			}
+			b.WriteByte('\n')
+		}
+		for _, child := range span.Children {
+			write(child, depth+1)
+		}
+	}
+	for _, span := range t.Spans {
+		write(span, 1)
+	}
+}
+
+func attributeMap(attrs []attribute.KeyValue) map[string]interface{} {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(attrs))
+	for _, attr := range attrs {
+		out[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	return out
+}
+
+// attributeText formats the attrs as {key=value, ...}, empty without attrs.
+func attributeText(attrs []attribute.KeyValue) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	list := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		list = append(list, fmt.Sprintf("%s=%s", attr.Key, attr.Value.Emit()))
+	}
+	return "{" + strings.Join(list, ", ") + "}"
+}
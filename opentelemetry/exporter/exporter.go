@@ -4,13 +4,20 @@ package exporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/console"
+	"github.com/razorpay/golib/opentelemetry/exporter/file"
+	"github.com/razorpay/golib/opentelemetry/exporter/memory"
 	"github.com/razorpay/golib/opentelemetry/exporter/opentelemetry"
 	"github.com/razorpay/golib/opentelemetry/exporter/prometheus"
+	"github.com/razorpay/golib/opentelemetry/exporter/remotewrite"
+	"github.com/razorpay/golib/opentelemetry/exporter/zipkin"
 
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
@@ -25,37 +32,99 @@ type SpanExporter interface {
 	SpanExporter() sdktrace.SpanExporter
 }
 
+// LogExporter is the interface required in order to export logs.
+type LogExporter interface {
+	LogExporter() sdklog.Exporter
+}
+
+// Shutdowner is implemented by exporters holding resources (connections,
+// servers, ...) that must be released once telemetry is torn down.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
 // Factory is the function type to obtain exporters, that can
-// implement [MetricReader], [SpanExporter] or both.
+// implement any combination of [MetricReader], [SpanExporter] and [LogExporter].
+// The context only bounds the creation: the instances holding resources
+// implement [Shutdowner] and are released by their Shutdown method, not
+// when the context is done.
 type Factory func(context.Context, map[string]interface{}) (interface{}, error)
 
-var (
-	exporterFactories map[config.ExporterKind]Factory
+var ErrDuplicateFactory = errors.New("exporter factory already registered")
 
-	mu           = new(sync.RWMutex)
-	registerOnce = new(sync.Once)
+var (
+	mu                = new(sync.RWMutex)
+	exporterFactories = map[config.ExporterKind]Factory{
+		prometheus.ExporterKey:    prometheus.CreateExporter,
+		opentelemetry.ExporterKey: opentelemetry.CreateExporter,
+		memory.ExporterKey:        memory.CreateExporter,
+		console.ExporterKey:       console.CreateExporter,
+		file.ExporterKey:          file.CreateExporter,
+		zipkin.ExporterKey:        zipkin.CreateExporter,
+		remotewrite.ExporterKey:   remotewrite.CreateExporter,
+	}
 )
 
-// RegisterKnownFactories registers all known exporter factories
-func RegisterKnownFactories() {
-	registerOnce.Do(func() {
-		mu.Lock()
-		defer mu.Unlock()
-		exporterFactories = map[config.ExporterKind]Factory{
-			prometheus.ExporterKey:    prometheus.CreateExporter,
-			opentelemetry.ExporterKey: opentelemetry.CreateExporter,
-		}
-	})
+// RegisterFactory makes the exporters created by factory available under
+// kind in the configuration. It fails if the kind is already taken. It is
+// safe for concurrent use, so that the modules of custom exporters can
+// register them in an init function:
+//
+//	func init() {
+//		if err := exporter.RegisterFactory("kafka", CreateExporter); err != nil {
+//			panic(err)
+//		}
+//	}
+func RegisterFactory(kind config.ExporterKind, factory Factory) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := exporterFactories[kind]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateFactory, kind)
+	}
+	exporterFactories[kind] = factory
+	return nil
+}
+
+// Unregister removes the factory of kind, which allows to replace the
+// factory of a known kind. It does nothing if the kind is not registered.
+func Unregister(kind config.ExporterKind) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(exporterFactories, kind)
+}
+
+// RegisterKnownFactories registers all known exporter factories.
+//
+// Deprecated: the known factories are registered when the package is
+// loaded, this function does nothing.
+func RegisterKnownFactories() {}
+
+func factory(kind config.ExporterKind) (Factory, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	f, ok := exporterFactories[kind]
+	return f, ok
 }
 
-// CreateInstances create instances for a given configuration.
-func CreateInstances(ctx context.Context, cfg []config.Exporter) (map[string]MetricReader, map[string]SpanExporter, []error) {
+// CreateInstances create instances for a given configuration. An instance
+// is registered under its name for every signal it supports, so that the
+// pipelines of the traces, metrics and logs can share it.
+//
+// The instances are not shut down when ctx is done, the caller owns them
+// and must call the Shutdown method of those implementing [Shutdowner].
+func CreateInstances(ctx context.Context, cfg []config.Exporter) (map[string]MetricReader, map[string]SpanExporter, map[string]LogExporter, []error) {
+	return CreateInstancesWithFactories(ctx, cfg, nil)
+}
+
+// CreateInstancesWithFactories behaves like [CreateInstances], the factories
+// given by kind being used in place of the registered ones, e.g. a factory
+// with options of an exporter package.
+func CreateInstancesWithFactories(ctx context.Context, cfg []config.Exporter, factories map[config.ExporterKind]Factory) (map[string]MetricReader, map[string]SpanExporter, map[string]LogExporter, []error) {
 	metricReaderMap := make(map[string]MetricReader)
 	spanExporterMap := make(map[string]SpanExporter)
+	logExporterMap := make(map[string]LogExporter)
 	var errList []error
 
-	mu.RLock()
-	defer mu.RUnlock()
 	uniqueNames := map[string]bool{}
 
 	for idx, exporterCfg := range cfg {
@@ -64,7 +133,10 @@ func CreateInstances(ctx context.Context, cfg []config.Exporter) (map[string]Met
 			errList = append(errList, err)
 			continue
 		}
-		f, ok := exporterFactories[exporterCfg.Kind]
+		f, ok := factories[exporterCfg.Kind]
+		if !ok {
+			f, ok = factory(exporterCfg.Kind)
+		}
 		if !ok {
 			err := fmt.Errorf("exporter %s of kind: %s (at idx %d) not found", exporterCfg.Name, exporterCfg.Kind, idx)
 			errList = append(errList, err)
@@ -78,16 +150,25 @@ func CreateInstances(ctx context.Context, cfg []config.Exporter) (map[string]Met
 		if exporterInstance == nil {
 			err := fmt.Errorf("implementation of kind: %s (at idx %d) creates nil instance", exporterCfg.Kind, idx)
 			errList = append(errList, err)
+			continue
 		}
 
 		uniqueNames[exporterCfg.Name] = true
-		if spanExporter, ok := exporterInstance.(SpanExporter); ok && spanExporter != nil {
+		spanExporter, isSpanExporter := exporterInstance.(SpanExporter)
+		if isSpanExporter && spanExporter != nil {
 			spanExporterMap[exporterCfg.Name] = spanExporter
-		} else if metricReader, ok := exporterInstance.(MetricReader); ok && metricReader != nil {
+		}
+		metricReader, isMetricReader := exporterInstance.(MetricReader)
+		if isMetricReader && metricReader != nil {
 			metricReaderMap[exporterCfg.Name] = metricReader
-		} else {
+		}
+		logExporter, isLogExporter := exporterInstance.(LogExporter)
+		if isLogExporter && logExporter != nil {
+			logExporterMap[exporterCfg.Name] = logExporter
+		}
+		if !isSpanExporter && !isMetricReader && !isLogExporter {
 			errList = append(errList, fmt.Errorf("kind %s (at idx %d) is not a exporter", exporterCfg.Kind, idx))
 		}
 	}
-	return metricReaderMap, spanExporterMap, errList
+	return metricReaderMap, spanExporterMap, logExporterMap, errList
 }
@@ -0,0 +1,85 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/opentelemetry"
+	"github.com/razorpay/golib/opentelemetry/exporter/prometheus"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCreateInstancesForAllSignals(t *testing.T) {
+	ctx := context.Background()
+	metricReaders, spanExporters, logExporters, errs := CreateInstances(ctx, []config.Exporter{
+		{Name: "otel", Kind: opentelemetry.ExporterKey},
+		{Name: "prom", Kind: prometheus.ExporterKey, Config: map[string]interface{}{"port": 0}},
+	})
+	require.Empty(t, errs)
+
+	collector := spanExporters["otel"]
+	require.NotNil(t, collector)
+	require.Same(t, collector, metricReaders["otel"])
+	require.Same(t, collector, logExporters["otel"])
+
+	require.Contains(t, metricReaders, "prom")
+	require.NotContains(t, spanExporters, "prom")
+	require.NotContains(t, logExporters, "prom")
+
+	require.NoError(t, collector.(Shutdowner).Shutdown(ctx))
+	require.NoError(t, metricReaders["prom"].(Shutdowner).Shutdown(ctx))
+}
+
+type spanExporter struct {
+	tracetest.InMemoryExporter
+}
+
+func (e *spanExporter) SpanExporter() sdktrace.SpanExporter {
+	return &e.InMemoryExporter
+}
+
+func TestRegisterFactory(t *testing.T) {
+	kind := config.ExporterKind("in_memory")
+	factory := func(context.Context, map[string]interface{}) (interface{}, error) {
+		return &spanExporter{}, nil
+	}
+	require.NoError(t, RegisterFactory(kind, factory))
+	t.Cleanup(func() { Unregister(kind) })
+	require.ErrorIs(t, RegisterFactory(kind, factory), ErrDuplicateFactory)
+	require.ErrorIs(t, RegisterFactory(prometheus.ExporterKey, factory), ErrDuplicateFactory)
+
+	_, spanExporters, _, errs := CreateInstances(context.Background(), []config.Exporter{{Name: "memory", Kind: kind}})
+	require.Empty(t, errs)
+	require.IsType(t, &spanExporter{}, spanExporters["memory"])
+
+	Unregister(kind)
+	_, _, _, errs = CreateInstances(context.Background(), []config.Exporter{{Name: "memory", Kind: kind}})
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "exporter memory of kind: in_memory (at idx 0) not found")
+}
+
+func TestRegisterFactoryConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		kind := config.ExporterKind(fmt.Sprintf("kind_%d", i))
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, RegisterFactory(kind, func(context.Context, map[string]interface{}) (interface{}, error) {
+				return &spanExporter{}, nil
+			}))
+			Unregister(kind)
+		}()
+		go func() {
+			defer wg.Done()
+			CreateInstances(context.Background(), []config.Exporter{{Name: "exporter", Kind: kind}})
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,120 @@
+// Package file implements an exporter writing the telemetry data to a
+// local file, in the OTLP/JSON encoding, to ship it later.
+package file
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/otlp"
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/periodic"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// ExporterKey is the name for the file exporter
+	ExporterKey = config.ExporterKind("file")
+
+	ExportIntervalMs = 60000
+	ExportTimeoutMs  = 30000
+)
+
+var ErrPathMissing = errors.New("path is required")
+
+// CollectorConfig has the variables to configure the file exporter
+type CollectorConfig struct {
+	// Path is the file the telemetry data is appended to, an OTLP/JSON export
+	// request per line. The rotated files are kept next to it, named after it
+	// with the time of the rotation (exp: telemetry-20240102T150405.000000000Z.jsonl)
+	Path string `json:"path"`
+	// MaxMegabytes rotates the file before it grows over this size, 0 disables the size rotation
+	MaxMegabytes int `json:"max_megabytes"`
+	// RotationIntervalMs rotates the file once it is open for this duration, 0 disables the time rotation
+	RotationIntervalMs int `json:"rotation_interval_ms"`
+	// Compress gzips the rotated files
+	Compress bool `json:"compress"`
+	// MaxBackups is the number of rotated files retained, the oldest ones being removed, 0 retains them all
+	MaxBackups int `json:"max_backups"`
+	// ExportIntervalMs is the interval between two writes of metrics
+	ExportIntervalMs int `json:"export_interval_ms"`
+	// ExportTimeoutMs is the timeout of a write of metrics
+	ExportTimeoutMs int `json:"export_timeout_ms"`
+}
+
+// Collector implements the traces, metrics and logs exporter, all the
+// signals being written to the same file.
+type Collector struct {
+	writer      *rotatingWriter
+	exporter    sdktrace.SpanExporter
+	logExporter sdklog.Exporter
+	reader      *periodic.LazyReader
+}
+
+// SpanExporter implements the interface to export traces.
+func (c *Collector) SpanExporter() sdktrace.SpanExporter {
+	return c.exporter
+}
+
+// MetricReader implements the interface to export metrics, written
+// periodically.
+func (c *Collector) MetricReader() sdkmetric.Reader {
+	return c.reader.Reader()
+}
+
+// LogExporter implements the interface to export logs.
+func (c *Collector) LogExporter() sdklog.Exporter {
+	return c.logExporter
+}
+
+// Shutdown closes the file, once the providers are shut down.
+func (c *Collector) Shutdown(context.Context) error {
+	return c.writer.Close()
+}
+
+// ParseConfig creates a file exporter configuration.
+func ParseConfig(in map[string]interface{}) (*CollectorConfig, error) {
+	defaultConfig := CollectorConfig{
+		ExportIntervalMs: ExportIntervalMs,
+		ExportTimeoutMs:  ExportTimeoutMs,
+	}
+	err := config.Parse(in, &defaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	if defaultConfig.Path == "" {
+		return nil, ErrPathMissing
+	}
+	return &defaultConfig, nil
+}
+
+// CreateExporter creates a file exporter instance, the file is created or
+// opened for appending.
+func CreateExporter(ctx context.Context, cfg map[string]interface{}) (interface{}, error) {
+	fileCfg, err := ParseConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := newRotatingWriter(fileCfg, time.Now)
+	if err != nil {
+		return nil, err
+	}
+	spanExporter, err := otlptrace.New(ctx, &otlp.TraceClient{Sender: writer})
+	if err != nil {
+		return nil, errors.Join(err, writer.Close())
+	}
+	return &Collector{
+		writer:      writer,
+		exporter:    spanExporter,
+		logExporter: otlp.NewLogExporter(writer),
+		reader: periodic.NewLazyReader(otlp.NewMetricExporter(writer, sdkmetric.DefaultTemporalitySelector),
+			sdkmetric.WithInterval(time.Duration(fileCfg.ExportIntervalMs)*time.Millisecond),
+			sdkmetric.WithTimeout(time.Duration(fileCfg.ExportTimeoutMs)*time.Millisecond),
+		),
+	}, nil
+}
@@ -0,0 +1,212 @@
+package file
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/otlp"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestParseConfig(t *testing.T) {
+	_, err := ParseConfig(map[string]interface{}{})
+	require.ErrorIs(t, err, ErrPathMissing)
+
+	cfg, err := ParseConfig(map[string]interface{}{"path": "/var/log/telemetry.jsonl", "max_megabytes": 10, "compress": true})
+	require.NoError(t, err)
+	require.Equal(t, &CollectorConfig{
+		Path:             "/var/log/telemetry.jsonl",
+		MaxMegabytes:     10,
+		Compress:         true,
+		ExportIntervalMs: ExportIntervalMs,
+		ExportTimeoutMs:  ExportTimeoutMs,
+	}, cfg)
+}
+
+func TestExporter(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "spool", "telemetry.jsonl")
+	instance, err := CreateExporter(ctx, map[string]interface{}{"path": path})
+	require.NoError(t, err)
+	collector := instance.(*Collector)
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(collector.SpanExporter()))
+	_, span := tracerProvider.Tracer("test").Start(ctx, "span")
+	span.End()
+
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(collector.MetricReader()))
+	counter, err := meterProvider.Meter("test").Int64Counter("counter")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	loggerProvider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(collector.LogExporter())))
+	var record log.Record
+	record.SetBody(log.StringValue("message"))
+	loggerProvider.Logger("test").Emit(ctx, record)
+
+	require.NoError(t, tracerProvider.Shutdown(ctx))
+	require.NoError(t, meterProvider.Shutdown(ctx))
+	require.NoError(t, loggerProvider.Shutdown(ctx))
+	require.NoError(t, collector.Shutdown(ctx))
+
+	lines := readLines(t, path)
+	require.Len(t, lines, 3)
+	var spans, metrics, logs map[string][]map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &spans))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &logs))
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &metrics))
+	require.Contains(t, lines[0], `"traceId":"`+span.SpanContext().TraceID().String()+`"`)
+	require.Len(t, spans["resourceSpans"], 1)
+	require.Len(t, logs["resourceLogs"], 1)
+	require.Contains(t, lines[2], `"name":"counter"`)
+	require.Len(t, metrics["resourceMetrics"], 1)
+
+	err = collector.writer.Send(ctx, request("late"))
+	require.ErrorIs(t, err, errClosed)
+}
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "telemetry.jsonl")
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	writer, err := newRotatingWriter(&CollectorConfig{
+		Path:               path,
+		RotationIntervalMs: int(time.Hour / time.Millisecond),
+		Compress:           true,
+		MaxBackups:         2,
+	}, func() time.Time { return now })
+	require.NoError(t, err)
+	defer writer.Close()
+	writer.maxSize = 100
+
+	// rotated by size: the second line does not fit.
+	require.NoError(t, writer.Send(ctx, request("first")))
+	require.NoError(t, writer.Send(ctx, request("second")))
+	// rotated by time.
+	now = now.Add(time.Hour + time.Minute)
+	require.NoError(t, writer.Send(ctx, request("third")))
+	now = now.Add(time.Hour)
+	require.NoError(t, writer.Send(ctx, request("fourth")))
+	// waits for the cleanup of the rotated files.
+	require.NoError(t, writer.Close())
+
+	backups, err := writer.backups()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "telemetry-20240102T160505.000000000Z.jsonl.gz"),
+		filepath.Join(dir, "telemetry-20240102T170505.000000000Z.jsonl.gz"),
+	}, backups, "the oldest backup is removed")
+	require.Equal(t, []string{string(mustMarshal(t, request("second")))}, readGzipLines(t, backups[0]))
+	require.Equal(t, []string{string(mustMarshal(t, request("third")))}, readGzipLines(t, backups[1]))
+	require.Equal(t, []string{string(mustMarshal(t, request("fourth")))}, readLines(t, path))
+}
+
+func TestRotationWithCleanupError(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "telemetry.jsonl")
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	writer, err := newRotatingWriter(&CollectorConfig{Path: path, Compress: true}, func() time.Time { return now })
+	require.NoError(t, err)
+	var errs []error
+	writer.handleError = func(err error) { errs = append(errs, err) }
+	writer.maxSize = 100
+	// the gzip of the first rotated file cannot be created.
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "telemetry-20240102T150405.000000000Z.jsonl.gz.tmp"), 0o755))
+
+	require.NoError(t, writer.Send(ctx, request("first")))
+	require.NoError(t, writer.Send(ctx, request("second")), "the line is written once the new file is open")
+	now = now.Add(time.Minute)
+	require.NoError(t, writer.Send(ctx, request("third")))
+	require.NoError(t, writer.Close())
+
+	require.Len(t, errs, 1, "the failed compression is reported once")
+	require.ErrorContains(t, errs[0], "compress")
+	require.Equal(t, []string{string(mustMarshal(t, request("first")))}, readLines(t, filepath.Join(dir, "telemetry-20240102T150405.000000000Z.jsonl")))
+	require.Equal(t, []string{string(mustMarshal(t, request("second")))}, readGzipLines(t, filepath.Join(dir, "telemetry-20240102T150505.000000000Z.jsonl.gz")))
+	require.Equal(t, []string{string(mustMarshal(t, request("third")))}, readLines(t, path))
+}
+
+func TestRotationAtTheSameTime(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "telemetry.jsonl")
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	writer, err := newRotatingWriter(&CollectorConfig{Path: path, Compress: true}, func() time.Time { return now })
+	require.NoError(t, err)
+	writer.maxSize = 100
+	// a backup compressed at the same time.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "telemetry-20240102T150405.000000000Z.jsonl.gz"), nil, 0o644))
+
+	for _, name := range []string{"first", "second", "third"} {
+		require.NoError(t, writer.Send(ctx, request(name)))
+	}
+	require.NoError(t, writer.Close())
+
+	backups, err := writer.backups()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "telemetry-20240102T150405.000000000Z.jsonl.gz"),
+		filepath.Join(dir, "telemetry-20240102T150405.000000000Z-1.jsonl.gz"),
+		filepath.Join(dir, "telemetry-20240102T150405.000000000Z-2.jsonl.gz"),
+	}, backups, "the backups are not replaced")
+	require.Equal(t, []string{string(mustMarshal(t, request("first")))}, readGzipLines(t, backups[1]))
+	require.Equal(t, []string{string(mustMarshal(t, request("second")))}, readGzipLines(t, backups[2]))
+	require.Equal(t, []string{string(mustMarshal(t, request("third")))}, readLines(t, path))
+}
+
+func request(name string) *collectortracepb.ExportTraceServiceRequest {
+	return &collectortracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{Name: name}}}},
+		}},
+	}
+}
+
+func mustMarshal(t *testing.T, req *collectortracepb.ExportTraceServiceRequest) []byte {
+	b, err := otlp.MarshalJSON(req)
+	require.NoError(t, err)
+	return b
+}
+
+func readLines(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	return scanLines(t, file)
+}
+
+func readGzipLines(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	return scanLines(t, gz)
+}
+
+func scanLines(t *testing.T, r io.Reader) []string {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
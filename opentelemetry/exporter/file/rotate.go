@@ -0,0 +1,361 @@
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/otlp"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+// backupTimeFormat is the time of the rotation in the name of the rotated
+// files, sorting them chronologically.
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+const megabyte = 1024 * 1024
+
+var errClosed = errors.New("file exporter is shut down")
+
+// rotatingWriter writes the export requests a line each to the file,
+// rotating it by size and time. It implements [otlp.Sender] for all the
+// signals, the file being closed by [rotatingWriter.Close] only.
+//
+// The rotated files are compressed and the oldest ones removed in the
+// background, not to stall the exports, their errors being given to
+// handleError.
+type rotatingWriter struct {
+	path        string
+	maxSize     int64
+	interval    time.Duration
+	compress    bool
+	maxBackups  int
+	now         func() time.Time
+	handleError func(error)
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	// rotated wakes up the cleanup of the rotated files.
+	rotated chan struct{}
+	done    chan struct{}
+	// uncompressed are the rotated files whose compression failed, reported
+	// once and not compressed again. It is only used by the cleanup.
+	uncompressed map[string]bool
+}
+
+var _ otlp.Sender = (*rotatingWriter)(nil)
+
+func newRotatingWriter(cfg *CollectorConfig, now func() time.Time) (*rotatingWriter, error) {
+	w := &rotatingWriter{
+		path:        cfg.Path,
+		maxSize:     int64(cfg.MaxMegabytes) * megabyte,
+		interval:    time.Duration(cfg.RotationIntervalMs) * time.Millisecond,
+		compress:    cfg.Compress,
+		maxBackups:  cfg.MaxBackups,
+		now:         now,
+		handleError: otel.Handle,
+		rotated:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	err := os.MkdirAll(filepath.Dir(w.path), 0o755)
+	if err != nil {
+		return nil, err
+	}
+	err = w.open()
+	if err != nil {
+		return nil, err
+	}
+	go w.cleanup()
+	return w, nil
+}
+
+// Send implements [otlp.Sender].
+func (w *rotatingWriter) Send(_ context.Context, request proto.Message) error {
+	line, err := otlp.MarshalJSON(request)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return errClosed
+	}
+	if w.file != nil && w.shouldRotate(int64(len(line))) {
+		err = w.rotate()
+		if err != nil && w.file == nil {
+			return fmt.Errorf("rotate %s: %w", w.path, err)
+		}
+		if err != nil {
+			// the line is written to the file still open.
+			w.handleError(fmt.Errorf("file exporter: rotate %s: %w", w.path, err))
+		}
+	}
+	if w.file == nil {
+		// the file could not be opened again by the last rotation.
+		err = w.open()
+		if err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+// Shutdown implements [otlp.Sender], the file staying open for the other
+// signals until Close.
+func (w *rotatingWriter) Shutdown(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Close closes the file, the later writes fail, and waits for the cleanup
+// of the rotated files.
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	close(w.rotated)
+	w.mu.Unlock()
+	<-w.done
+	return err
+}
+
+func (w *rotatingWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+	w.file, w.size, w.openedAt = file, info.Size(), w.now()
+	return nil
+}
+
+// shouldRotate tells whether the file must be rotated before writing n
+// bytes, a line larger than the max size being written to an empty file.
+func (w *rotatingWriter) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.maxSize > 0 && w.size+n > w.maxSize {
+		return true
+	}
+	return w.interval > 0 && w.now().Sub(w.openedAt) >= w.interval
+}
+
+// rotate renames the file after the time of the rotation and opens a new
+// one, then wakes up the cleanup of the rotated files. The file is nil when
+// it could not be opened again.
+func (w *rotatingWriter) rotate() error {
+	err := w.file.Close()
+	w.file = nil
+	if err != nil {
+		return err
+	}
+	backup, err := w.backupName(w.now())
+	if err == nil {
+		err = os.Rename(w.path, backup)
+	}
+	if err != nil {
+		// keeps appending to the current file.
+		return errors.Join(err, w.open())
+	}
+	err = w.open()
+	if err != nil {
+		return err
+	}
+	select {
+	case w.rotated <- struct{}{}:
+	default:
+		// a cleanup is already pending, it will see this file too.
+	}
+	return nil
+}
+
+// cleanup compresses the rotated files and removes the oldest ones after
+// each rotation, until the writer is closed.
+func (w *rotatingWriter) cleanup() {
+	defer close(w.done)
+	for range w.rotated {
+		if w.compress {
+			err := w.compressBackups()
+			if err != nil {
+				w.handleError(fmt.Errorf("file exporter: compress %s: %w", w.path, err))
+			}
+		}
+		err := w.removeOldBackups()
+		if err != nil {
+			w.handleError(fmt.Errorf("file exporter: remove the backups of %s: %w", w.path, err))
+		}
+	}
+}
+
+// compressBackups compresses the rotated files which are not yet, the ones
+// which failed before being skipped.
+func (w *rotatingWriter) compressBackups() error {
+	list, err := w.backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	uncompressed := map[string]bool{}
+	for _, backup := range list {
+		if strings.HasSuffix(backup, ".gz") {
+			continue
+		}
+		if w.uncompressed[backup] {
+			uncompressed[backup] = true
+			continue
+		}
+		err = compress(backup)
+		if err != nil {
+			uncompressed[backup] = true
+			errs = append(errs, err)
+		}
+	}
+	// forgets the removed files.
+	w.uncompressed = uncompressed
+	return errors.Join(errs...)
+}
+
+// backupName returns the name of the file rotated at t: the name of the
+// file with the time before its extension, followed by a sequence number
+// when a file rotated at the same time exists, compressed or not.
+func (w *rotatingWriter) backupName(t time.Time) (string, error) {
+	ext := filepath.Ext(w.path)
+	base := fmt.Sprintf("%s-%s", strings.TrimSuffix(w.path, ext), t.UTC().Format(backupTimeFormat))
+	for seq := 0; ; seq++ {
+		name := base + ext
+		if seq > 0 {
+			name = fmt.Sprintf("%s-%d%s", base, seq, ext)
+		}
+		exists := false
+		for _, candidate := range []string{name, name + ".gz"} {
+			_, err := os.Lstat(candidate)
+			if err == nil {
+				exists = true
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+		if !exists {
+			return name, nil
+		}
+	}
+}
+
+// backups returns the rotated files, from the oldest to the newest.
+func (w *rotatingWriter) backups() ([]string, error) {
+	dir := filepath.Dir(w.path)
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(filepath.Base(w.path), ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type backup struct {
+		path string
+		time time.Time
+		seq  int
+	}
+	var found []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		timestamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz"), ext)
+		seq := 0
+		if i := strings.LastIndexByte(timestamp, '-'); i >= 0 {
+			seq, err = strconv.Atoi(timestamp[i+1:])
+			if err != nil || seq <= 0 {
+				continue
+			}
+			timestamp = timestamp[:i]
+		}
+		t, err := time.Parse(backupTimeFormat, timestamp)
+		if err != nil {
+			continue
+		}
+		found = append(found, backup{path: filepath.Join(dir, name), time: t, seq: seq})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if !found[i].time.Equal(found[j].time) {
+			return found[i].time.Before(found[j].time)
+		}
+		return found[i].seq < found[j].seq
+	})
+	list := make([]string, 0, len(found))
+	for _, b := range found {
+		list = append(list, b.path)
+	}
+	return list, nil
+}
+
+func (w *rotatingWriter) removeOldBackups() error {
+	if w.maxBackups <= 0 {
+		return nil
+	}
+	list, err := w.backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for len(list) > w.maxBackups {
+		errs = append(errs, os.Remove(list[0]))
+		list = list[1:]
+	}
+	return errors.Join(errs...)
+}
+
+// compress replaces the file at path by its gzip, path.gz. The gzip is
+// written to path.gz.tmp first, so that path.gz is only ever complete.
+func compress(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := path + ".gz.tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	err = errors.Join(err, gz.Close(), out.Close())
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,139 @@
+package otlp
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/protobuf/proto"
+
+	collectorlogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// Sender delivers OTLP export requests to their destination.
+type Sender interface {
+	Send(ctx context.Context, request proto.Message) error
+	Shutdown(ctx context.Context) error
+}
+
+// TraceClient implements [otlptrace.Client] over a [Sender], the spans
+// conversion being done by [otlptrace.Exporter].
+type TraceClient struct {
+	Sender Sender
+}
+
+var _ otlptrace.Client = (*TraceClient)(nil)
+
+// Start implements [otlptrace.Client].
+func (c *TraceClient) Start(context.Context) error {
+	return nil
+}
+
+// Stop implements [otlptrace.Client].
+func (c *TraceClient) Stop(ctx context.Context) error {
+	return c.Sender.Shutdown(ctx)
+}
+
+// UploadTraces implements [otlptrace.Client].
+func (c *TraceClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	if len(protoSpans) == 0 {
+		return nil
+	}
+	return c.Sender.Send(ctx, &collectortracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+}
+
+// MetricExporter implements [sdkmetric.Exporter] over a [Sender].
+type MetricExporter struct {
+	sender              Sender
+	temporalitySelector sdkmetric.TemporalitySelector
+	shutdownOnce        sync.Once
+}
+
+var _ sdkmetric.Exporter = (*MetricExporter)(nil)
+
+// NewMetricExporter creates a metric exporter sending through sender.
+func NewMetricExporter(sender Sender, temporalitySelector sdkmetric.TemporalitySelector) *MetricExporter {
+	return &MetricExporter{
+		sender:              sender,
+		temporalitySelector: temporalitySelector,
+	}
+}
+
+// Temporality implements [sdkmetric.Exporter].
+func (e *MetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return e.temporalitySelector(kind)
+}
+
+// Aggregation implements [sdkmetric.Exporter].
+func (e *MetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+// Export implements [sdkmetric.Exporter].
+func (e *MetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	resourceMetrics, err := ResourceMetrics(rm)
+	if err != nil {
+		return err
+	}
+	return e.sender.Send(ctx, &collectormetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{resourceMetrics},
+	})
+}
+
+// ForceFlush implements [sdkmetric.Exporter], nothing is buffered.
+func (e *MetricExporter) ForceFlush(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Shutdown implements [sdkmetric.Exporter].
+func (e *MetricExporter) Shutdown(ctx context.Context) error {
+	var err error
+	e.shutdownOnce.Do(func() {
+		err = e.sender.Shutdown(ctx)
+	})
+	return err
+}
+
+// LogExporter implements [sdklog.Exporter] over a [Sender].
+type LogExporter struct {
+	sender       Sender
+	shutdownOnce sync.Once
+}
+
+var _ sdklog.Exporter = (*LogExporter)(nil)
+
+// NewLogExporter creates a log exporter sending through sender.
+func NewLogExporter(sender Sender) *LogExporter {
+	return &LogExporter{sender: sender}
+}
+
+// Export implements [sdklog.Exporter].
+func (e *LogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return e.sender.Send(ctx, &collectorlogspb.ExportLogsServiceRequest{
+		ResourceLogs: ResourceLogs(records),
+	})
+}
+
+// ForceFlush implements [sdklog.Exporter], nothing is buffered.
+func (e *LogExporter) ForceFlush(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Shutdown implements [sdklog.Exporter].
+func (e *LogExporter) Shutdown(ctx context.Context) error {
+	var err error
+	e.shutdownOnce.Do(func() {
+		err = e.sender.Shutdown(ctx)
+	})
+	return err
+}
@@ -0,0 +1,61 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// HTTPJSONSender posts the requests to an OTLP/HTTP endpoint with the
+// JSON encoding.
+type HTTPJSONSender struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+// NewHTTPJSONSender creates a sender posting to url with client, the
+// headers being added to every request.
+func NewHTTPJSONSender(url string, client *http.Client, headers map[string]string) *HTTPJSONSender {
+	return &HTTPJSONSender{
+		client:  client,
+		url:     url,
+		headers: headers,
+	}
+}
+
+// Send implements [Sender].
+func (s *HTTPJSONSender) Send(ctx context.Context, request proto.Message) error {
+	body, err := MarshalJSON(request)
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range s.headers {
+		httpRequest.Header.Set(name, value)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("otlp export to %s failed: %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+// Shutdown implements [Sender].
+func (s *HTTPJSONSender) Shutdown(ctx context.Context) error {
+	s.client.CloseIdleConnections()
+	return ctx.Err()
+}
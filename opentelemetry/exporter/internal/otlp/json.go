@@ -0,0 +1,64 @@
+package otlp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// idFields are the bytes fields the OTLP JSON encoding represents in
+// hex instead of the base64 of the protobuf JSON mapping.
+var idFields = map[string]bool{
+	"traceId":      true,
+	"spanId":       true,
+	"parentSpanId": true,
+}
+
+// MarshalJSON encodes an OTLP message following the OTLP/JSON rules:
+// lowerCamelCase field names, enums as integers and hex trace/span ids.
+func MarshalJSON(msg proto.Message) ([]byte, error) {
+	b, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if err := hexIDs(doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func hexIDs(node interface{}) error {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if s, ok := value.(string); ok && idFields[key] {
+				id, err := base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					return err
+				}
+				n[key] = hex.EncodeToString(id)
+				continue
+			}
+			if err := hexIDs(value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, value := range n {
+			if err := hexIDs(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package otlp
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+// ResourceLogs converts log records, grouped by resource and scope.
+func ResourceLogs(records []sdklog.Record) []*logspb.ResourceLogs {
+	var out []*logspb.ResourceLogs
+	resourceLogs := map[attributeKey]*logspb.ResourceLogs{}
+	scopeLogs := map[attributeKey]map[instrumentation.Scope]*logspb.ScopeLogs{}
+	for i := range records {
+		record := &records[i]
+		res := record.Resource()
+		resKey := resourceKey(&res)
+		rl, ok := resourceLogs[resKey]
+		if !ok {
+			rl = &logspb.ResourceLogs{
+				Resource:  resourceProto(&res),
+				SchemaUrl: res.SchemaURL(),
+			}
+			resourceLogs[resKey] = rl
+			scopeLogs[resKey] = map[instrumentation.Scope]*logspb.ScopeLogs{}
+			out = append(out, rl)
+		}
+		scope := record.InstrumentationScope()
+		sl, ok := scopeLogs[resKey][scope]
+		if !ok {
+			sl = &logspb.ScopeLogs{
+				Scope:     scopeProto(scope),
+				SchemaUrl: scope.SchemaURL,
+			}
+			scopeLogs[resKey][scope] = sl
+			rl.ScopeLogs = append(rl.ScopeLogs, sl)
+		}
+		sl.LogRecords = append(sl.LogRecords, logRecordProto(record))
+	}
+	return out
+}
+
+type attributeKey string
+
+var resourceEncoder = attribute.DefaultEncoder()
+
+func resourceKey(res *resource.Resource) attributeKey {
+	return attributeKey(res.SchemaURL() + "|" + res.Encoded(resourceEncoder))
+}
+
+func logRecordProto(record *sdklog.Record) *logspb.LogRecord {
+	out := &logspb.LogRecord{
+		TimeUnixNano:           timeProto(record.Timestamp()),
+		ObservedTimeUnixNano:   timeProto(record.ObservedTimestamp()),
+		SeverityNumber:         logspb.SeverityNumber(record.Severity()),
+		SeverityText:           record.SeverityText(),
+		Body:                   logValueProto(record.Body()),
+		DroppedAttributesCount: uint32(record.DroppedAttributes()),
+		Flags:                  uint32(record.TraceFlags()),
+	}
+	if traceID := record.TraceID(); traceID.IsValid() {
+		out.TraceId = traceID[:]
+	}
+	if spanID := record.SpanID(); spanID.IsValid() {
+		out.SpanId = spanID[:]
+	}
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		out.Attributes = append(out.Attributes, &commonpb.KeyValue{
+			Key:   kv.Key,
+			Value: logValueProto(kv.Value),
+		})
+		return true
+	})
+	return out
+}
+
+func logValueProto(v log.Value) *commonpb.AnyValue {
+	switch v.Kind() {
+	case log.KindBool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case log.KindInt64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case log.KindFloat64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case log.KindString:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.AsString()}}
+	case log.KindBytes:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: v.AsBytes()}}
+	case log.KindSlice:
+		values := make([]*commonpb.AnyValue, 0, len(v.AsSlice()))
+		for _, item := range v.AsSlice() {
+			values = append(values, logValueProto(item))
+		}
+		return arrayValueProto(values)
+	case log.KindMap:
+		kvs := make([]*commonpb.KeyValue, 0, len(v.AsMap()))
+		for _, kv := range v.AsMap() {
+			kvs = append(kvs, &commonpb.KeyValue{Key: kv.Key, Value: logValueProto(kv.Value)})
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: kvs}}}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,215 @@
+package otlp
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// ResourceMetrics converts the metrics collected by a reader.
+func ResourceMetrics(rm *metricdata.ResourceMetrics) (*metricspb.ResourceMetrics, error) {
+	out := &metricspb.ResourceMetrics{
+		Resource:  resourceProto(rm.Resource),
+		SchemaUrl: rm.Resource.SchemaURL(),
+	}
+	for _, sm := range rm.ScopeMetrics {
+		scopeMetrics := &metricspb.ScopeMetrics{
+			Scope:     scopeProto(sm.Scope),
+			SchemaUrl: sm.Scope.SchemaURL,
+		}
+		for _, m := range sm.Metrics {
+			metric, err := metricProto(m)
+			if err != nil {
+				return nil, err
+			}
+			scopeMetrics.Metrics = append(scopeMetrics.Metrics, metric)
+		}
+		out.ScopeMetrics = append(out.ScopeMetrics, scopeMetrics)
+	}
+	return out, nil
+}
+
+func metricProto(m metricdata.Metrics) (*metricspb.Metric, error) {
+	out := &metricspb.Metric{
+		Name:        m.Name,
+		Description: m.Description,
+		Unit:        m.Unit,
+	}
+	switch data := m.Data.(type) {
+	case metricdata.Gauge[int64]:
+		out.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: dataPointsProto(data.DataPoints)}}
+	case metricdata.Gauge[float64]:
+		out.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: dataPointsProto(data.DataPoints)}}
+	case metricdata.Sum[int64]:
+		out.Data = &metricspb.Metric_Sum{Sum: sumProto(data)}
+	case metricdata.Sum[float64]:
+		out.Data = &metricspb.Metric_Sum{Sum: sumProto(data)}
+	case metricdata.Histogram[int64]:
+		out.Data = &metricspb.Metric_Histogram{Histogram: histogramProto(data)}
+	case metricdata.Histogram[float64]:
+		out.Data = &metricspb.Metric_Histogram{Histogram: histogramProto(data)}
+	case metricdata.ExponentialHistogram[int64]:
+		out.Data = &metricspb.Metric_ExponentialHistogram{ExponentialHistogram: exponentialHistogramProto(data)}
+	case metricdata.ExponentialHistogram[float64]:
+		out.Data = &metricspb.Metric_ExponentialHistogram{ExponentialHistogram: exponentialHistogramProto(data)}
+	case metricdata.Summary:
+		out.Data = &metricspb.Metric_Summary{Summary: summaryProto(data)}
+	default:
+		return nil, fmt.Errorf("unsupported aggregation %T of metric %s", m.Data, m.Name)
+	}
+	return out, nil
+}
+
+func temporalityProto(t metricdata.Temporality) metricspb.AggregationTemporality {
+	switch t {
+	case metricdata.DeltaTemporality:
+		return metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+	case metricdata.CumulativeTemporality:
+		return metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	default:
+		return metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED
+	}
+}
+
+func sumProto[N int64 | float64](sum metricdata.Sum[N]) *metricspb.Sum {
+	return &metricspb.Sum{
+		DataPoints:             dataPointsProto(sum.DataPoints),
+		AggregationTemporality: temporalityProto(sum.Temporality),
+		IsMonotonic:            sum.IsMonotonic,
+	}
+}
+
+func dataPointsProto[N int64 | float64](dataPoints []metricdata.DataPoint[N]) []*metricspb.NumberDataPoint {
+	out := make([]*metricspb.NumberDataPoint, 0, len(dataPoints))
+	for _, dp := range dataPoints {
+		ndp := &metricspb.NumberDataPoint{
+			Attributes:        attributesProto(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: timeProto(dp.StartTime),
+			TimeUnixNano:      timeProto(dp.Time),
+			Exemplars:         exemplarsProto(dp.Exemplars),
+		}
+		switch v := any(dp.Value).(type) {
+		case int64:
+			ndp.Value = &metricspb.NumberDataPoint_AsInt{AsInt: v}
+		case float64:
+			ndp.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: v}
+		}
+		out = append(out, ndp)
+	}
+	return out
+}
+
+func histogramProto[N int64 | float64](histogram metricdata.Histogram[N]) *metricspb.Histogram {
+	dataPoints := make([]*metricspb.HistogramDataPoint, 0, len(histogram.DataPoints))
+	for _, dp := range histogram.DataPoints {
+		sum := float64(dp.Sum)
+		hdp := &metricspb.HistogramDataPoint{
+			Attributes:        attributesProto(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: timeProto(dp.StartTime),
+			TimeUnixNano:      timeProto(dp.Time),
+			Count:             dp.Count,
+			Sum:               &sum,
+			BucketCounts:      dp.BucketCounts,
+			ExplicitBounds:    dp.Bounds,
+			Exemplars:         exemplarsProto(dp.Exemplars),
+		}
+		if v, ok := dp.Min.Value(); ok {
+			minimum := float64(v)
+			hdp.Min = &minimum
+		}
+		if v, ok := dp.Max.Value(); ok {
+			maximum := float64(v)
+			hdp.Max = &maximum
+		}
+		dataPoints = append(dataPoints, hdp)
+	}
+	return &metricspb.Histogram{
+		DataPoints:             dataPoints,
+		AggregationTemporality: temporalityProto(histogram.Temporality),
+	}
+}
+
+func exponentialHistogramProto[N int64 | float64](histogram metricdata.ExponentialHistogram[N]) *metricspb.ExponentialHistogram {
+	dataPoints := make([]*metricspb.ExponentialHistogramDataPoint, 0, len(histogram.DataPoints))
+	for _, dp := range histogram.DataPoints {
+		sum := float64(dp.Sum)
+		edp := &metricspb.ExponentialHistogramDataPoint{
+			Attributes:        attributesProto(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: timeProto(dp.StartTime),
+			TimeUnixNano:      timeProto(dp.Time),
+			Count:             dp.Count,
+			Sum:               &sum,
+			Scale:             dp.Scale,
+			ZeroCount:         dp.ZeroCount,
+			ZeroThreshold:     dp.ZeroThreshold,
+			Positive: &metricspb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       dp.PositiveBucket.Offset,
+				BucketCounts: dp.PositiveBucket.Counts,
+			},
+			Negative: &metricspb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       dp.NegativeBucket.Offset,
+				BucketCounts: dp.NegativeBucket.Counts,
+			},
+			Exemplars: exemplarsProto(dp.Exemplars),
+		}
+		if v, ok := dp.Min.Value(); ok {
+			minimum := float64(v)
+			edp.Min = &minimum
+		}
+		if v, ok := dp.Max.Value(); ok {
+			maximum := float64(v)
+			edp.Max = &maximum
+		}
+		dataPoints = append(dataPoints, edp)
+	}
+	return &metricspb.ExponentialHistogram{
+		DataPoints:             dataPoints,
+		AggregationTemporality: temporalityProto(histogram.Temporality),
+	}
+}
+
+func summaryProto(summary metricdata.Summary) *metricspb.Summary {
+	dataPoints := make([]*metricspb.SummaryDataPoint, 0, len(summary.DataPoints))
+	for _, dp := range summary.DataPoints {
+		sdp := &metricspb.SummaryDataPoint{
+			Attributes:        attributesProto(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: timeProto(dp.StartTime),
+			TimeUnixNano:      timeProto(dp.Time),
+			Count:             dp.Count,
+			Sum:               dp.Sum,
+		}
+		for _, qv := range dp.QuantileValues {
+			sdp.QuantileValues = append(sdp.QuantileValues, &metricspb.SummaryDataPoint_ValueAtQuantile{
+				Quantile: qv.Quantile,
+				Value:    qv.Value,
+			})
+		}
+		dataPoints = append(dataPoints, sdp)
+	}
+	return &metricspb.Summary{DataPoints: dataPoints}
+}
+
+func exemplarsProto[N int64 | float64](exemplars []metricdata.Exemplar[N]) []*metricspb.Exemplar {
+	if len(exemplars) == 0 {
+		return nil
+	}
+	out := make([]*metricspb.Exemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		exemplar := &metricspb.Exemplar{
+			FilteredAttributes: attributesProto(e.FilteredAttributes),
+			TimeUnixNano:       timeProto(e.Time),
+			SpanId:             e.SpanID,
+			TraceId:            e.TraceID,
+		}
+		switch v := any(e.Value).(type) {
+		case int64:
+			exemplar.Value = &metricspb.Exemplar_AsInt{AsInt: v}
+		case float64:
+			exemplar.Value = &metricspb.Exemplar_AsDouble{AsDouble: v}
+		}
+		out = append(out, exemplar)
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+
+	collectorlogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+type recordingSender struct {
+	requests []proto.Message
+}
+
+func (s *recordingSender) Send(_ context.Context, request proto.Message) error {
+	s.requests = append(s.requests, request)
+	return nil
+}
+
+func (s *recordingSender) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestResourceMetrics(t *testing.T) {
+	now := time.Now()
+	rm := &metricdata.ResourceMetrics{
+		Resource: resource.NewSchemaless(attribute.String("service.name", "test-service")),
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope: instrumentation.Scope{Name: "test"},
+			Metrics: []metricdata.Metrics{
+				{
+					Name: "requests",
+					Data: metricdata.Sum[int64]{
+						Temporality: metricdata.CumulativeTemporality,
+						IsMonotonic: true,
+						DataPoints: []metricdata.DataPoint[int64]{{
+							Attributes: attribute.NewSet(attribute.String("route", "/payments")),
+							Time:       now,
+							Value:      5,
+						}},
+					},
+				},
+				{
+					Name: "latency",
+					Data: metricdata.Histogram[float64]{
+						Temporality: metricdata.DeltaTemporality,
+						DataPoints: []metricdata.HistogramDataPoint[float64]{{
+							Count:        2,
+							Sum:          3.5,
+							Bounds:       []float64{1, 5},
+							BucketCounts: []uint64{0, 2, 0},
+							Max:          metricdata.NewExtrema(2.5),
+						}},
+					},
+				},
+			},
+		}},
+	}
+	out, err := ResourceMetrics(rm)
+	require.NoError(t, err)
+	require.Equal(t, "service.name", out.Resource.Attributes[0].Key)
+	metrics := out.ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+
+	sum := metrics[0].GetSum()
+	require.True(t, sum.IsMonotonic)
+	require.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.AggregationTemporality)
+	require.Equal(t, int64(5), sum.DataPoints[0].GetAsInt())
+	require.Equal(t, uint64(now.UnixNano()), sum.DataPoints[0].TimeUnixNano)
+
+	histogram := metrics[1].GetHistogram()
+	require.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, histogram.AggregationTemporality)
+	require.Equal(t, []uint64{0, 2, 0}, histogram.DataPoints[0].BucketCounts)
+	require.Equal(t, 2.5, histogram.DataPoints[0].GetMax())
+	require.Nil(t, histogram.DataPoints[0].Min)
+}
+
+func TestLogExporter(t *testing.T) {
+	sender := &recordingSender{}
+	exporter := NewLogExporter(sender)
+
+	var record sdklog.Record
+	record.SetBody(log.StringValue("payment captured"))
+	record.SetSeverity(log.SeverityInfo)
+	record.SetTraceID(trace.TraceID{1})
+	record.SetSpanID(trace.SpanID{2})
+	record.AddAttributes(log.Int("amount", 100))
+	require.NoError(t, exporter.Export(context.Background(), []sdklog.Record{record, record}))
+
+	require.Len(t, sender.requests, 1)
+	request := sender.requests[0].(*collectorlogspb.ExportLogsServiceRequest)
+	require.Len(t, request.ResourceLogs, 1)
+	records := request.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 2)
+	require.Equal(t, "payment captured", records[0].Body.GetStringValue())
+	require.Equal(t, "amount", records[0].Attributes[0].Key)
+
+	b, err := MarshalJSON(request)
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &doc))
+	logRecord := doc["resourceLogs"].([]interface{})[0].(map[string]interface{})["scopeLogs"].([]interface{})[0].(map[string]interface{})["logRecords"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "01000000000000000000000000000000", logRecord["traceId"])
+	require.Equal(t, "0200000000000000", logRecord["spanId"])
+	require.Equal(t, float64(9), logRecord["severityNumber"])
+}
@@ -0,0 +1,93 @@
+// Package otlp converts the SDK telemetry data to OTLP protobuf messages
+// and exports them through a [Sender], for the transports and formats not
+// provided by the upstream OTLP exporters.
+package otlp
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func resourceProto(res *resource.Resource) *resourcepb.Resource {
+	if res == nil {
+		return &resourcepb.Resource{}
+	}
+	return &resourcepb.Resource{Attributes: attributesProto(res.Attributes())}
+}
+
+func scopeProto(scope instrumentation.Scope) *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{
+		Name:    scope.Name,
+		Version: scope.Version,
+	}
+}
+
+func attributesProto(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, &commonpb.KeyValue{
+			Key:   string(kv.Key),
+			Value: attributeValueProto(kv.Value),
+		})
+	}
+	return out
+}
+
+func attributeValueProto(v attribute.Value) *commonpb.AnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case attribute.STRING:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.AsString()}}
+	case attribute.BOOLSLICE:
+		values := make([]*commonpb.AnyValue, 0, len(v.AsBoolSlice()))
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: b}})
+		}
+		return arrayValueProto(values)
+	case attribute.INT64SLICE:
+		values := make([]*commonpb.AnyValue, 0, len(v.AsInt64Slice()))
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}})
+		}
+		return arrayValueProto(values)
+	case attribute.FLOAT64SLICE:
+		values := make([]*commonpb.AnyValue, 0, len(v.AsFloat64Slice()))
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}})
+		}
+		return arrayValueProto(values)
+	case attribute.STRINGSLICE:
+		values := make([]*commonpb.AnyValue, 0, len(v.AsStringSlice()))
+		for _, s := range v.AsStringSlice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}})
+		}
+		return arrayValueProto(values)
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Emit()}}
+	}
+}
+
+func arrayValueProto(values []*commonpb.AnyValue) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+}
+
+func timeProto(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
@@ -0,0 +1,47 @@
+// Package periodic creates the readers pushing the metrics of the
+// exporters periodically.
+package periodic
+
+import (
+	"context"
+	"sync"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// LazyReader creates the periodic reader of a metric exporter on the first
+// call to [LazyReader.Reader], the reader being then owned by the meter
+// provider it is registered with.
+type LazyReader struct {
+	exporter sdkmetric.Exporter
+	opts     []sdkmetric.PeriodicReaderOption
+
+	mu     sync.Mutex
+	reader sdkmetric.Reader
+}
+
+// NewLazyReader creates a LazyReader pushing to exporter with opts.
+func NewLazyReader(exporter sdkmetric.Exporter, opts ...sdkmetric.PeriodicReaderOption) *LazyReader {
+	return &LazyReader{exporter: exporter, opts: opts}
+}
+
+// Reader returns the periodic reader, created on the first call.
+func (r *LazyReader) Reader() sdkmetric.Reader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reader == nil {
+		r.reader = sdkmetric.NewPeriodicReader(r.exporter, r.opts...)
+	}
+	return r.reader
+}
+
+// Shutdown shuts the exporter down when the reader was never created, the
+// meter provider shutting it down through the reader otherwise.
+func (r *LazyReader) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reader != nil {
+		return nil
+	}
+	return r.exporter.Shutdown(ctx)
+}
@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var headerReference = regexp.MustCompile(`\$\{(env|file):([^}]+)\}`)
+
+// ResolveHeaders returns a copy of headers where the references to an
+// environment variable (${env:NAME}) or to the content of a file
+// (${file:/path/to/file}) are replaced by their value.
+func ResolveHeaders(headers map[string]string) (map[string]string, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	resolved := make(map[string]string, len(headers))
+	for name, value := range headers {
+		var err error
+		resolved[name], err = ResolveValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+	}
+	return resolved, nil
+}
+
+// ResolveValue replaces the references to an environment variable
+// (${env:NAME}) or to the content of a file (${file:/path/to/file}) of
+// value by their value.
+//
+// The exporters resolve their secrets (headers, passwords, tokens) with it
+// when they are created, so that these are not written in the configuration.
+func ResolveValue(value string) (string, error) {
+	var err error
+	resolved := headerReference.ReplaceAllStringFunc(value, func(reference string) string {
+		match := headerReference.FindStringSubmatch(reference)
+		source, key := match[1], match[2]
+		if source == "env" {
+			v, ok := os.LookupEnv(key)
+			if !ok {
+				err = fmt.Errorf("environment variable %s is not set", key)
+			}
+			return v
+		}
+		content, readErr := os.ReadFile(key)
+		if readErr != nil {
+			err = readErr
+		}
+		return strings.TrimSpace(string(content))
+	})
+	return resolved, err
+}
@@ -0,0 +1,221 @@
+// Package transport builds the TLS configuration and the headers of the
+// connections opened by the exporters to remote servers, and the TLS
+// configuration of the servers started by the exporters.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var ErrTLSWithInsecure = errors.New("tls settings require insecure to be false")
+var ErrCertWithoutKey = errors.New("cert_file and key_file must be provided together")
+var ErrServerCertMissing = errors.New("cert_file and key_file are required")
+var ErrServerNameMissing = errors.New("server_name is required to verify the server certificate")
+
+// TLSConfig has the variables to secure the connection to a remote server.
+type TLSConfig struct {
+	// Insecure disables TLS
+	Insecure bool `json:"insecure"`
+	// CAFile is the PEM file of the CAs verifying the server, the system CAs are used if empty
+	CAFile string `json:"ca_file"`
+	// CertFile is the PEM file of the client certificate for mTLS
+	CertFile string `json:"cert_file"`
+	// KeyFile is the PEM file of the client key for mTLS
+	KeyFile string `json:"key_file"`
+	// ServerName overrides the host as the name used to verify the server certificate
+	ServerName string `json:"server_name"`
+}
+
+// ClientTLSConfig creates the tls.Config described by cfg for connecting to
+// host, nil if cfg is insecure. The CA and client certificate files are read
+// again on the handshakes following their modification, so rotated
+// certificates are picked up without restarting. The server certificate is
+// verified against the server_name of cfg, the host otherwise, including
+// when it is an IP address.
+func ClientTLSConfig(cfg TLSConfig, host string) (*tls.Config, error) {
+	if cfg.Insecure {
+		if cfg.CAFile != "" || cfg.CertFile != "" || cfg.KeyFile != "" || cfg.ServerName != "" {
+			return nil, ErrTLSWithInsecure
+		}
+		return nil, nil
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, ErrCertWithoutKey
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	r := &reloader{cfg: cfg, serverName: cfg.ServerName}
+	if r.serverName == "" {
+		r.serverName = host
+	}
+	if cfg.CertFile != "" {
+		if _, err := r.certificate(); err != nil {
+			return nil, err
+		}
+		tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.certificate()
+		}
+	}
+	if cfg.CAFile != "" {
+		// Without a name, the certificate of any host would be accepted.
+		if r.serverName == "" {
+			return nil, ErrServerNameMissing
+		}
+		if _, err := r.rootCAs(); err != nil {
+			return nil, err
+		}
+		// The verification is done by VerifyConnection against the
+		// current CAs, as RootCAs cannot be swapped after creation, and
+		// the server name captured above, as crypto/tls does not set
+		// the ServerName of the connection state for IP addresses.
+		tlsCfg.InsecureSkipVerify = true
+		tlsCfg.VerifyConnection = r.verifyConnection
+	}
+	return tlsCfg, nil
+}
+
+// ServerTLSConfig has the variables to secure a server started by an exporter.
+type ServerTLSConfig struct {
+	// CertFile is the PEM file of the server certificate
+	CertFile string `json:"cert_file"`
+	// KeyFile is the PEM file of the server key
+	KeyFile string `json:"key_file"`
+	// ClientCAFile is the PEM file of the CAs verifying the client certificates,
+	// which are required when it is provided (mTLS)
+	ClientCAFile string `json:"client_ca_file"`
+}
+
+// NewServerTLSConfig creates the tls.Config described by cfg. Like for
+// [ClientTLSConfig], the files are read again after their modification.
+func NewServerTLSConfig(cfg ServerTLSConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, ErrServerCertMissing
+	}
+	r := &reloader{cfg: TLSConfig{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, CAFile: cfg.ClientCAFile}}
+	if _, err := r.certificate(); err != nil {
+		return nil, err
+	}
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.certificate()
+		},
+	}
+	if cfg.ClientCAFile != "" {
+		if _, err := r.rootCAs(); err != nil {
+			return nil, err
+		}
+		// ClientCAs cannot be swapped after creation, each handshake gets
+		// a configuration with the current CAs.
+		tlsCfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			pool, err := r.rootCAs()
+			if err != nil {
+				return nil, err
+			}
+			clientCfg := tlsCfg.Clone()
+			clientCfg.GetConfigForClient = nil
+			clientCfg.ClientCAs = pool
+			clientCfg.ClientAuth = tls.RequireAndVerifyClientCert
+			return clientCfg, nil
+		}
+	}
+	return tlsCfg, nil
+}
+
+// reloader caches the content of the TLS files until they are modified.
+type reloader struct {
+	cfg        TLSConfig
+	serverName string
+
+	mu          sync.Mutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	pool        *x509.CertPool
+	caModTime   time.Time
+}
+
+func (r *reloader) certificate() (*tls.Certificate, error) {
+	certModTime, err := modTime(r.cfg.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	keyModTime, err := modTime(r.cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cert != nil && certModTime.Equal(r.certModTime) && keyModTime.Equal(r.keyModTime) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	r.cert, r.certModTime, r.keyModTime = &cert, certModTime, keyModTime
+	return r.cert, nil
+}
+
+func (r *reloader) rootCAs() (*x509.CertPool, error) {
+	caModTime, err := modTime(r.cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pool != nil && caModTime.Equal(r.caModTime) {
+		return r.pool, nil
+	}
+	pem, err := os.ReadFile(r.cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in ca_file %s", r.cfg.CAFile)
+	}
+	r.pool, r.caModTime = pool, caModTime
+	return r.pool, nil
+}
+
+// verifyConnection verifies the server certificate chain and name like
+// the crypto/tls default verification, with the current CAs.
+func (r *reloader) verifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server did not provide a certificate")
+	}
+	pool, err := r.rootCAs()
+	if err != nil {
+		return err
+	}
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		DNSName:       r.serverName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err = cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+func modTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
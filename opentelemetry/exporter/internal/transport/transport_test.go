@@ -0,0 +1,223 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca *testCA) issue(t *testing.T, extKeyUsage x509.ExtKeyUsage) tls.Certificate {
+	return ca.issueFor(t, extKeyUsage, net.ParseIP("127.0.0.1"))
+}
+
+func (ca *testCA) issueFor(t *testing.T, extKeyUsage x509.ExtKeyUsage, ip net.IP) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{extKeyUsage},
+		IPAddresses:  []net.IP{ip},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func writeFile(t *testing.T, path string, content []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func writeKeyPair(t *testing.T, certFile, keyFile string, cert tls.Certificate, modTime time.Time) {
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+	writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), modTime)
+	writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), modTime)
+}
+
+func TestClientTLSConfigInsecure(t *testing.T) {
+	tlsCfg, err := ClientTLSConfig(TLSConfig{Insecure: true}, "localhost")
+	require.NoError(t, err)
+	require.Nil(t, tlsCfg)
+
+	_, err = ClientTLSConfig(TLSConfig{Insecure: true, ServerName: "collector"}, "localhost")
+	require.ErrorIs(t, err, ErrTLSWithInsecure)
+	_, err = ClientTLSConfig(TLSConfig{KeyFile: "key.pem"}, "localhost")
+	require.ErrorIs(t, err, ErrCertWithoutKey)
+	_, err = ClientTLSConfig(TLSConfig{CAFile: "ca.pem"}, "")
+	require.ErrorIs(t, err, ErrServerNameMissing)
+}
+
+func TestClientTLSConfigReloadsRotatedFiles(t *testing.T) {
+	oldCA := newTestCA(t, "old-ca")
+	newCA := newTestCA(t, "new-ca")
+	clientCA := newTestCA(t, "client-ca")
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{newCA.issue(t, x509.ExtKeyUsageServerAuth)},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	modTime := time.Now().Add(-time.Minute)
+	writeFile(t, caFile, oldCA.pem, modTime)
+	writeKeyPair(t, certFile, keyFile, oldCA.issue(t, x509.ExtKeyUsageClientAuth), modTime)
+
+	tlsCfg, err := ClientTLSConfig(TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}, "127.0.0.1")
+	require.NoError(t, err)
+	get := func() error {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg, DisableKeepAlives: true}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	require.Error(t, get())
+
+	writeFile(t, caFile, newCA.pem, modTime.Add(time.Second))
+	writeKeyPair(t, certFile, keyFile, clientCA.issue(t, x509.ExtKeyUsageClientAuth), modTime.Add(time.Second))
+	require.NoError(t, get())
+}
+
+func TestClientTLSConfigVerifiesServerName(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{ca.issueFor(t, x509.ExtKeyUsageServerAuth, net.ParseIP("10.0.0.1"))}}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	writeFile(t, caFile, ca.pem, time.Now())
+	get := func(cfg TLSConfig, host string) error {
+		tlsCfg, err := ClientTLSConfig(cfg, host)
+		require.NoError(t, err)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg, DisableKeepAlives: true}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	// The certificate is signed by the CA but issued for another IP.
+	require.ErrorContains(t, get(TLSConfig{CAFile: caFile}, "127.0.0.1"), "127.0.0.1")
+	require.Error(t, get(TLSConfig{CAFile: caFile, ServerName: "collector"}, "127.0.0.1"))
+	require.NoError(t, get(TLSConfig{CAFile: caFile, ServerName: "10.0.0.1"}, "127.0.0.1"))
+}
+
+func TestResolveHeaders(t *testing.T) {
+	t.Setenv("API_KEY", "key-from-env")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token-from-file\n"), 0o600))
+
+	headers, err := ResolveHeaders(map[string]string{
+		"X-Api-Key":     "${env:API_KEY}",
+		"Authorization": "Bearer ${file:" + tokenFile + "}",
+		"X-Tenant":      "payments",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"X-Api-Key":     "key-from-env",
+		"Authorization": "Bearer token-from-file",
+		"X-Tenant":      "payments",
+	}, headers)
+
+	_, err = ResolveHeaders(map[string]string{"X-Api-Key": "${env:UNSET_API_KEY}"})
+	require.ErrorContains(t, err, "UNSET_API_KEY")
+	_, err = ResolveHeaders(map[string]string{"Authorization": "${file:/does/not/exist}"})
+	require.Error(t, err)
+}
+
+func TestNewServerTLSConfig(t *testing.T) {
+	serverCA := newTestCA(t, "server-ca")
+	clientCA := newTestCA(t, "client-ca")
+	dir := t.TempDir()
+	modTime := time.Now()
+	writeKeyPair(t, filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), serverCA.issue(t, x509.ExtKeyUsageServerAuth), modTime)
+	writeKeyPair(t, filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem"), clientCA.issue(t, x509.ExtKeyUsageClientAuth), modTime)
+	writeFile(t, filepath.Join(dir, "server-ca.pem"), serverCA.pem, modTime)
+	writeFile(t, filepath.Join(dir, "client-ca.pem"), clientCA.pem, modTime)
+
+	_, err := NewServerTLSConfig(ServerTLSConfig{CertFile: filepath.Join(dir, "server.pem")})
+	require.ErrorIs(t, err, ErrServerCertMissing)
+
+	serverTLS, err := NewServerTLSConfig(ServerTLSConfig{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		ClientCAFile: filepath.Join(dir, "client-ca.pem"),
+	})
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = serverTLS
+	server.StartTLS()
+	defer server.Close()
+
+	get := func(cfg TLSConfig) error {
+		tlsCfg, err := ClientTLSConfig(cfg, "127.0.0.1")
+		require.NoError(t, err)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg, DisableKeepAlives: true}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	require.Error(t, get(TLSConfig{CAFile: filepath.Join(dir, "server-ca.pem")}))
+	require.NoError(t, get(TLSConfig{
+		CAFile:   filepath.Join(dir, "server-ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client-key.pem"),
+	}))
+}
@@ -0,0 +1,121 @@
+// Package memory implements an exporter keeping the telemetry data in
+// memory, for the tests.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	// ExporterKey is the name for the memory exporter
+	ExporterKey = config.ExporterKind("memory")
+)
+
+// Collector implements the traces, metrics and logs exporter, recording
+// them in memory until they are read.
+type Collector struct {
+	spans  *tracetest.InMemoryExporter
+	reader *sdkmetric.ManualReader
+	logs   *logExporter
+}
+
+// SpanExporter implements the interface to export traces.
+func (c *Collector) SpanExporter() sdktrace.SpanExporter {
+	return c.spans
+}
+
+// MetricReader implements the interface to export metrics. The metrics are
+// collected when read with [Collector.Metrics].
+func (c *Collector) MetricReader() sdkmetric.Reader {
+	return c.reader
+}
+
+// LogExporter implements the interface to export logs.
+func (c *Collector) LogExporter() sdklog.Exporter {
+	return c.logs
+}
+
+// Spans returns the spans exported so far.
+func (c *Collector) Spans() tracetest.SpanStubs {
+	return c.spans.GetSpans()
+}
+
+// Metrics collects the current state of the metrics.
+func (c *Collector) Metrics(ctx context.Context) (metricdata.ResourceMetrics, error) {
+	var rm metricdata.ResourceMetrics
+	err := c.reader.Collect(ctx, &rm)
+	return rm, err
+}
+
+// Logs returns the log records exported so far.
+func (c *Collector) Logs() []sdklog.Record {
+	return c.logs.records()
+}
+
+// Reset forgets the spans and log records exported so far.
+func (c *Collector) Reset() {
+	c.spans.Reset()
+	c.logs.reset()
+}
+
+// Shutdown releases the recorded telemetry data.
+func (c *Collector) Shutdown(ctx context.Context) error {
+	err := c.spans.Shutdown(ctx)
+	c.logs.reset()
+	return err
+}
+
+// CreateExporter creates a memory exporter instance, it has no settings.
+func CreateExporter(_ context.Context, _ map[string]interface{}) (interface{}, error) {
+	return &Collector{
+		spans:  tracetest.NewInMemoryExporter(),
+		reader: sdkmetric.NewManualReader(),
+		logs:   &logExporter{},
+	}, nil
+}
+
+// logExporter records the exported log records.
+type logExporter struct {
+	mu   sync.Mutex
+	list []sdklog.Record
+}
+
+func (e *logExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, record := range records {
+		e.list = append(e.list, record.Clone())
+	}
+	return nil
+}
+
+func (e *logExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func (e *logExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *logExporter) records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.list...)
+}
+
+func (e *logExporter) reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = nil
+}
+
+var _ sdklog.Exporter = (*logExporter)(nil)
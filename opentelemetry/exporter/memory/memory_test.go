@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestCollector(t *testing.T) {
+	ctx := context.Background()
+	instance, err := CreateExporter(ctx, nil)
+	require.NoError(t, err)
+	collector := instance.(*Collector)
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(collector.SpanExporter()))
+	_, span := tracerProvider.Tracer("test").Start(ctx, "span")
+	span.End()
+	require.Len(t, collector.Spans(), 1)
+	require.Equal(t, "span", collector.Spans()[0].Name)
+
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(collector.MetricReader()))
+	counter, err := meterProvider.Meter("test").Int64Counter("counter")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+	metrics, err := collector.Metrics(ctx)
+	require.NoError(t, err)
+	require.Len(t, metrics.ScopeMetrics, 1)
+	require.Equal(t, "counter", metrics.ScopeMetrics[0].Metrics[0].Name)
+
+	loggerProvider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(collector.LogExporter())))
+	var record log.Record
+	record.SetBody(log.StringValue("message"))
+	loggerProvider.Logger("test").Emit(ctx, record)
+	require.Len(t, collector.Logs(), 1)
+
+	collector.Reset()
+	require.Empty(t, collector.Spans())
+	require.Empty(t, collector.Logs())
+	require.NoError(t, collector.Shutdown(ctx))
+}
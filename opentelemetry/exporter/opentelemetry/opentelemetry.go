@@ -3,13 +3,14 @@ package opentelemetry
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"time"
 
 	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/periodic"
 
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -18,6 +19,12 @@ const (
 	ExporterKey      = config.ExporterKind("opentelemetry")
 	RemoteServerPort = 4317
 	RemoteServerHost = "localhost"
+
+	ExportIntervalMs      = 60000
+	ExportTimeoutMs       = 30000
+	TemporalityCumulative = "cumulative"
+	TemporalityDelta      = "delta"
+	TemporalityLowMemory  = "lowmemory"
 )
 
 // CollectorConfig has the variables to configure
@@ -25,13 +32,38 @@ const (
 type CollectorConfig struct {
 	// Host is the host of remote server receiving telemetry data (exp: otel-collector endpoint host)
 	Host string `json:"host"`
-	// Port is the port of remote server receiving telemetry data (exp: otel-collector endpoint port)
+	// Port is the port of remote server receiving telemetry data (exp: otel-collector endpoint port),
+	// defaults to 4317 for grpc and 4318 for the http protocols
 	Port int `json:"port"`
+	// Protocol is the OTLP transport: grpc, http/protobuf or http/json
+	Protocol string `json:"protocol"`
+	// Path is the base path of the http endpoints, the signal paths (exp: /v1/traces) are appended to it
+	Path string `json:"path"`
+	// Insecure disables TLS, it must be set to false to use the TLS settings below
+	Insecure bool `json:"insecure"`
+	// CAFile is the PEM file of the CAs verifying the server, the system CAs are used if empty
+	CAFile string `json:"ca_file"`
+	// CertFile is the PEM file of the client certificate for mTLS
+	CertFile string `json:"cert_file"`
+	// KeyFile is the PEM file of the client key for mTLS
+	KeyFile string `json:"key_file"`
+	// ServerName overrides the host as the name used to verify the server certificate
+	ServerName string `json:"server_name"`
+	// Headers are sent with every export, their values accept the references resolved by [transport.ResolveHeaders]
+	Headers map[string]string `json:"headers"`
+	// ExportIntervalMs is the interval between two pushes of metrics
+	ExportIntervalMs int `json:"export_interval_ms"`
+	// ExportTimeoutMs is the timeout of a push of metrics
+	ExportTimeoutMs int `json:"export_timeout_ms"`
+	// TemporalityPreference is the temporality of the pushed metrics: cumulative, delta or lowmemory
+	TemporalityPreference string `json:"temporality_preference"`
 }
 
-// Collector implements the traces exporter.
+// Collector implements the traces, metrics and logs exporter.
 type Collector struct {
-	exporter sdktrace.SpanExporter
+	exporter    sdktrace.SpanExporter
+	logExporter sdklog.Exporter
+	reader      *periodic.LazyReader
 }
 
 // SpanExporter implements the interface to export traces.
@@ -39,16 +71,40 @@ func (c *Collector) SpanExporter() sdktrace.SpanExporter {
 	return c.exporter
 }
 
+// MetricReader implements the interface to export metrics, pushed
+// periodically.
+func (c *Collector) MetricReader() sdkmetric.Reader {
+	return c.reader.Reader()
+}
+
+// LogExporter implements the interface to export logs.
+func (c *Collector) LogExporter() sdklog.Exporter {
+	return c.logExporter
+}
+
+// Shutdown flushes and closes the connections to the remote server.
+func (c *Collector) Shutdown(ctx context.Context) error {
+	return errors.Join(c.exporter.Shutdown(ctx), c.logExporter.Shutdown(ctx), c.reader.Shutdown(ctx))
+}
+
 // ParseConfig creates an Open Telemetry configuration.
 func ParseConfig(in map[string]interface{}) (*CollectorConfig, error) {
 	defaultConfig := CollectorConfig{
-		Host: RemoteServerHost,
-		Port: RemoteServerPort,
+		Host:                  RemoteServerHost,
+		Port:                  RemoteServerPort,
+		Protocol:              ProtocolGRPC,
+		Insecure:              true,
+		ExportIntervalMs:      ExportIntervalMs,
+		ExportTimeoutMs:       ExportTimeoutMs,
+		TemporalityPreference: TemporalityCumulative,
 	}
 	err := config.Parse(in, &defaultConfig)
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := in["port"]; !ok && defaultConfig.Protocol != ProtocolGRPC {
+		defaultConfig.Port = RemoteServerHTTPPort
+	}
 	return &defaultConfig, nil
 }
 
@@ -58,21 +114,20 @@ func CreateExporter(ctx context.Context, cfg map[string]interface{}) (interface{
 	if err != nil {
 		return nil, err
 	}
-
-	var exporter sdktrace.SpanExporter
-	exporter, err = otlptracegrpc.New(ctx,
-		otlptracegrpc.WithInsecure(),
-		otlptracegrpc.WithEndpoint(fmt.Sprintf("%s:%d", otelCfg.Host, otelCfg.Port)))
+	temporalitySelector, err := temporalitySelector(otelCfg.TemporalityPreference)
+	if err != nil {
+		return nil, err
+	}
+	exporters, err := newSignalExporters(ctx, otelCfg, temporalitySelector)
 	if err != nil {
 		return nil, err
 	}
-	go func() {
-		<-ctx.Done()
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		_ = exporter.Shutdown(ctx)
-	}()
 	return &Collector{
-		exporter: exporter,
+		exporter:    exporters.span,
+		logExporter: exporters.log,
+		reader: periodic.NewLazyReader(exporters.metric,
+			sdkmetric.WithInterval(time.Duration(otelCfg.ExportIntervalMs)*time.Millisecond),
+			sdkmetric.WithTimeout(time.Duration(otelCfg.ExportTimeoutMs)*time.Millisecond),
+		),
 	}, nil
 }
@@ -2,22 +2,38 @@ package opentelemetry
 
 import (
 	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestConfigFromInterface(t *testing.T) {
 	cfg := map[string]interface{}{
-		"port": 4317,
-		"host": "localhost1",
+		"port":                   4317,
+		"host":                   "localhost1",
+		"export_interval_ms":     1000,
+		"export_timeout_ms":      500,
+		"temporality_preference": "delta",
 	}
 	collectorConfig, err := ParseConfig(cfg)
 	require.NoError(t, err)
 	expectedConfig := &CollectorConfig{
-		Port: 4317,
-		Host: "localhost1",
+		Port:                  4317,
+		Host:                  "localhost1",
+		Protocol:              ProtocolGRPC,
+		Insecure:              true,
+		ExportIntervalMs:      1000,
+		ExportTimeoutMs:       500,
+		TemporalityPreference: TemporalityDelta,
 	}
 	require.Equal(t, expectedConfig, collectorConfig)
 
@@ -25,10 +41,24 @@ func TestConfigFromInterface(t *testing.T) {
 	collectorConfig, err = ParseConfig(cfg)
 	require.NoError(t, err)
 	expectedConfig = &CollectorConfig{
-		Port: 4317,
-		Host: "localhost",
+		Port:                  4317,
+		Host:                  "localhost",
+		Protocol:              ProtocolGRPC,
+		Insecure:              true,
+		ExportIntervalMs:      ExportIntervalMs,
+		ExportTimeoutMs:       ExportTimeoutMs,
+		TemporalityPreference: TemporalityCumulative,
 	}
 	require.Equal(t, expectedConfig, collectorConfig)
+
+	cfg = map[string]interface{}{
+		"protocol": "http/protobuf",
+		"path":     "/otlp",
+	}
+	collectorConfig, err = ParseConfig(cfg)
+	require.NoError(t, err)
+	require.Equal(t, RemoteServerHTTPPort, collectorConfig.Port)
+	require.Equal(t, "/otlp", collectorConfig.Path)
 }
 
 func TestExporter(t *testing.T) {
@@ -36,11 +66,99 @@ func TestExporter(t *testing.T) {
 		"port": 4317,
 		"host": "localhost1",
 	}
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx := context.Background()
 	exporterInstance, err := CreateExporter(ctx, cfg)
 	require.NoError(t, err)
-	_, ok := exporterInstance.(*Collector)
+	collector, ok := exporterInstance.(*Collector)
 	require.True(t, ok)
-	cancel()
-	time.Sleep(time.Millisecond * 200)
+	require.NotNil(t, collector.SpanExporter())
+	require.NotNil(t, collector.LogExporter())
+	require.NoError(t, collector.Shutdown(ctx))
+}
+
+func TestMetricExporter(t *testing.T) {
+	cfg := map[string]interface{}{
+		"export_interval_ms": 1000,
+	}
+	ctx := context.Background()
+	exporterInstance, err := CreateExporter(ctx, cfg)
+	require.NoError(t, err)
+	collector, ok := exporterInstance.(*Collector)
+	require.True(t, ok)
+	reader := collector.MetricReader()
+	require.NotNil(t, reader)
+	require.Same(t, reader, collector.MetricReader())
+	require.NoError(t, collector.Shutdown(ctx))
+	require.NoError(t, reader.Shutdown(ctx))
+}
+
+func TestExporterWithUnknownTemporality(t *testing.T) {
+	cfg := map[string]interface{}{
+		"temporality_preference": "sometimes",
+	}
+	_, err := CreateExporter(context.Background(), cfg)
+	require.ErrorContains(t, err, "unknown temporality preference")
+}
+
+func TestExporterWithProtocols(t *testing.T) {
+	for _, protocol := range []string{ProtocolGRPC, ProtocolHTTPProtobuf, ProtocolHTTPJSON} {
+		ctx := context.Background()
+		exporterInstance, err := CreateExporter(ctx, map[string]interface{}{"protocol": protocol})
+		require.NoError(t, err, protocol)
+		collector, ok := exporterInstance.(*Collector)
+		require.True(t, ok)
+		require.NoError(t, collector.Shutdown(ctx), protocol)
+	}
+	_, err := CreateExporter(context.Background(), map[string]interface{}{"protocol": "thrift"})
+	require.ErrorContains(t, err, "unknown protocol")
+}
+
+func TestExporterWithHTTPJSON(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan string, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- string(body)
+	}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600)
+	require.NoError(t, err)
+	t.Setenv("OTLP_TOKEN", "secret")
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	exporterInstance, err := CreateExporter(ctx, map[string]interface{}{
+		"protocol": ProtocolHTTPJSON,
+		"host":     serverURL.Hostname(),
+		"port":     port,
+		"path":     "/otlp/",
+		"insecure": false,
+		"ca_file":  caFile,
+		"headers":  map[string]interface{}{"Authorization": "Bearer ${env:OTLP_TOKEN}"},
+	})
+	require.NoError(t, err)
+	collector := exporterInstance.(*Collector)
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(collector.SpanExporter()))
+	_, span := tracerProvider.Tracer("test").Start(ctx, "json-span")
+	span.End()
+
+	request := <-requests
+	require.Equal(t, "/otlp/v1/traces", request.URL.Path)
+	require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	require.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+	require.Contains(t, <-bodies, `"name":"json-span"`)
+	require.NoError(t, tracerProvider.Shutdown(ctx))
+	require.NoError(t, collector.Shutdown(ctx))
+}
+
+func TestExporterWithInvalidTLSConfig(t *testing.T) {
+	_, err := CreateExporter(context.Background(), map[string]interface{}{"ca_file": "ca.pem"})
+	require.ErrorContains(t, err, "insecure")
+	_, err = CreateExporter(context.Background(), map[string]interface{}{"insecure": false, "cert_file": "cert.pem"})
+	require.ErrorContains(t, err, "key_file")
 }
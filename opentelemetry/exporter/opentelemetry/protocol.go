@@ -0,0 +1,180 @@
+package opentelemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/otlp"
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/transport"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+	ProtocolHTTPJSON     = "http/json"
+	RemoteServerHTTPPort = 4318
+
+	tracesPath  = "/v1/traces"
+	metricsPath = "/v1/metrics"
+	logsPath    = "/v1/logs"
+	httpTimeout = 10 * time.Second
+)
+
+// signalExporters are the exporters of every signal for a protocol.
+type signalExporters struct {
+	span   sdktrace.SpanExporter
+	metric sdkmetric.Exporter
+	log    sdklog.Exporter
+}
+
+// connection has the settings shared by the exporters of every signal.
+type connection struct {
+	endpoint string
+	tls      *tls.Config
+	headers  map[string]string
+}
+
+func newSignalExporters(ctx context.Context, cfg *CollectorConfig, temporalitySelector sdkmetric.TemporalitySelector) (*signalExporters, error) {
+	tlsCfg, err := transport.ClientTLSConfig(transport.TLSConfig{
+		Insecure:   cfg.Insecure,
+		CAFile:     cfg.CAFile,
+		CertFile:   cfg.CertFile,
+		KeyFile:    cfg.KeyFile,
+		ServerName: cfg.ServerName,
+	}, cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := transport.ResolveHeaders(cfg.Headers)
+	if err != nil {
+		return nil, err
+	}
+	conn := &connection{
+		endpoint: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		tls:      tlsCfg,
+		headers:  headers,
+	}
+
+	switch cfg.Protocol {
+	case ProtocolGRPC:
+		return newGRPCExporters(ctx, conn, temporalitySelector)
+	case ProtocolHTTPProtobuf:
+		return newHTTPProtobufExporters(ctx, cfg, conn, temporalitySelector)
+	case ProtocolHTTPJSON:
+		return newHTTPJSONExporters(ctx, cfg, conn, temporalitySelector)
+	default:
+		return nil, fmt.Errorf("unknown protocol: %s", cfg.Protocol)
+	}
+}
+
+func newGRPCExporters(ctx context.Context, conn *connection, temporalitySelector sdkmetric.TemporalitySelector) (*signalExporters, error) {
+	traceOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(conn.endpoint), otlptracegrpc.WithHeaders(conn.headers)}
+	metricOpts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(conn.endpoint), otlpmetricgrpc.WithHeaders(conn.headers),
+		otlpmetricgrpc.WithTemporalitySelector(temporalitySelector)}
+	logOpts := []otlploggrpc.Option{otlploggrpc.WithEndpoint(conn.endpoint), otlploggrpc.WithHeaders(conn.headers)}
+	if conn.tls == nil {
+		traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
+		metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
+		logOpts = append(logOpts, otlploggrpc.WithInsecure())
+	} else {
+		creds := credentials.NewTLS(conn.tls)
+		traceOpts = append(traceOpts, otlptracegrpc.WithTLSCredentials(creds))
+		metricOpts = append(metricOpts, otlpmetricgrpc.WithTLSCredentials(creds))
+		logOpts = append(logOpts, otlploggrpc.WithTLSCredentials(creds))
+	}
+
+	spanExporter, err := otlptracegrpc.New(ctx, traceOpts...)
+	if err != nil {
+		return nil, err
+	}
+	metricExporter, err := otlpmetricgrpc.New(ctx, metricOpts...)
+	if err != nil {
+		return nil, errors.Join(err, spanExporter.Shutdown(ctx))
+	}
+	logExporter, err := otlploggrpc.New(ctx, logOpts...)
+	if err != nil {
+		return nil, errors.Join(err, spanExporter.Shutdown(ctx), metricExporter.Shutdown(ctx))
+	}
+	return &signalExporters{span: spanExporter, metric: metricExporter, log: logExporter}, nil
+}
+
+func newHTTPProtobufExporters(ctx context.Context, cfg *CollectorConfig, conn *connection, temporalitySelector sdkmetric.TemporalitySelector) (*signalExporters, error) {
+	traceOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(conn.endpoint), otlptracehttp.WithHeaders(conn.headers),
+		otlptracehttp.WithURLPath(cfg.signalPath(tracesPath))}
+	metricOpts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(conn.endpoint), otlpmetrichttp.WithHeaders(conn.headers),
+		otlpmetrichttp.WithURLPath(cfg.signalPath(metricsPath)), otlpmetrichttp.WithTemporalitySelector(temporalitySelector)}
+	logOpts := []otlploghttp.Option{otlploghttp.WithEndpoint(conn.endpoint), otlploghttp.WithHeaders(conn.headers),
+		otlploghttp.WithURLPath(cfg.signalPath(logsPath))}
+	if conn.tls == nil {
+		traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+		metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+		logOpts = append(logOpts, otlploghttp.WithInsecure())
+	} else {
+		traceOpts = append(traceOpts, otlptracehttp.WithTLSClientConfig(conn.tls))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithTLSClientConfig(conn.tls))
+		logOpts = append(logOpts, otlploghttp.WithTLSClientConfig(conn.tls))
+	}
+
+	spanExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return nil, err
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		return nil, errors.Join(err, spanExporter.Shutdown(ctx))
+	}
+	logExporter, err := otlploghttp.New(ctx, logOpts...)
+	if err != nil {
+		return nil, errors.Join(err, spanExporter.Shutdown(ctx), metricExporter.Shutdown(ctx))
+	}
+	return &signalExporters{span: spanExporter, metric: metricExporter, log: logExporter}, nil
+}
+
+// newHTTPJSONExporters creates exporters encoding the requests in JSON,
+// which is not supported by the upstream OTLP exporters.
+func newHTTPJSONExporters(ctx context.Context, cfg *CollectorConfig, conn *connection, temporalitySelector sdkmetric.TemporalitySelector) (*signalExporters, error) {
+	scheme := "https"
+	if conn.tls == nil {
+		scheme = "http"
+	}
+	newSender := func(signalPath string) otlp.Sender {
+		url := fmt.Sprintf("%s://%s%s", scheme, conn.endpoint, cfg.signalPath(signalPath))
+		client := &http.Client{
+			Timeout:   httpTimeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: conn.tls},
+		}
+		return otlp.NewHTTPJSONSender(url, client, conn.headers)
+	}
+	spanExporter, err := otlptrace.New(ctx, &otlp.TraceClient{Sender: newSender(tracesPath)})
+	if err != nil {
+		return nil, err
+	}
+	return &signalExporters{
+		span:   spanExporter,
+		metric: otlp.NewMetricExporter(newSender(metricsPath), temporalitySelector),
+		log:    otlp.NewLogExporter(newSender(logsPath)),
+	}, nil
+}
+
+// signalPath appends the path of a signal to the configured base path.
+func (c *CollectorConfig) signalPath(signalPath string) string {
+	return strings.TrimSuffix(c.Path, "/") + signalPath
+}
@@ -0,0 +1,44 @@
+package opentelemetry
+
+import (
+	"fmt"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// temporalitySelector returns the selector matching the OTLP temporality
+// preference, as defined by OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE.
+func temporalitySelector(preference string) (sdkmetric.TemporalitySelector, error) {
+	switch preference {
+	case TemporalityCumulative:
+		return sdkmetric.DefaultTemporalitySelector, nil
+	case TemporalityDelta:
+		return deltaTemporalitySelector, nil
+	case TemporalityLowMemory:
+		return lowMemoryTemporalitySelector, nil
+	default:
+		return nil, fmt.Errorf("unknown temporality preference: %s", preference)
+	}
+}
+
+func deltaTemporalitySelector(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindCounter,
+		sdkmetric.InstrumentKindHistogram,
+		sdkmetric.InstrumentKindObservableCounter:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+func lowMemoryTemporalitySelector(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindCounter,
+		sdkmetric.InstrumentKindHistogram:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
@@ -0,0 +1,29 @@
+package opentelemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTemporalitySelector(t *testing.T) {
+	tests := []struct {
+		preference string
+		kind       sdkmetric.InstrumentKind
+		expected   metricdata.Temporality
+	}{
+		{TemporalityCumulative, sdkmetric.InstrumentKindCounter, metricdata.CumulativeTemporality},
+		{TemporalityDelta, sdkmetric.InstrumentKindCounter, metricdata.DeltaTemporality},
+		{TemporalityDelta, sdkmetric.InstrumentKindObservableCounter, metricdata.DeltaTemporality},
+		{TemporalityDelta, sdkmetric.InstrumentKindUpDownCounter, metricdata.CumulativeTemporality},
+		{TemporalityLowMemory, sdkmetric.InstrumentKindHistogram, metricdata.DeltaTemporality},
+		{TemporalityLowMemory, sdkmetric.InstrumentKindObservableCounter, metricdata.CumulativeTemporality},
+	}
+	for _, tt := range tests {
+		selector, err := temporalitySelector(tt.preference)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, selector(tt.kind), "%s: %s", tt.preference, tt.kind)
+	}
+}
@@ -0,0 +1,190 @@
+package prometheus
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// exemplarFeature is the environment variable enabling the exemplars of
+// the SDK, which are still an experimental feature.
+const exemplarFeature = "OTEL_GO_X_EXEMPLAR"
+
+// exemplarsEnabled tells whether the SDK records exemplars. The variable is
+// read by the SDK when the instruments are created, for every meter provider
+// of the process, so it is left to the deployment to set it.
+func exemplarsEnabled() bool {
+	return strings.EqualFold(os.Getenv(exemplarFeature), "true")
+}
+
+// createdTimestampGatherer sets the created timestamp of the counters and
+// histograms of the SDK to the start time of their data points, when the SDK
+// started accumulating them. The series of the instruments it does not find
+// in the collected metrics are left without created timestamp.
+type createdTimestampGatherer struct {
+	prom.Gatherer
+	reader sdkmetric.Reader
+	names  nameOptions
+}
+
+// startKey identifies the instrument of a series, as the instruments of
+// several scopes can share a metric family.
+type startKey struct {
+	family, scopeName, scopeVersion string
+}
+
+func (g *createdTimestampGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.Gatherer.Gather()
+	// collected after the series, so that all their instruments are known.
+	// The error is already reported by the collector of the exporter.
+	var rm metricdata.ResourceMetrics
+	if collectErr := g.reader.Collect(context.Background(), &rm); collectErr != nil {
+		return families, err
+	}
+	starts := map[startKey]*timestamppb.Timestamp{}
+	for _, scopeMetrics := range rm.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			if family, start, ok := g.names.start(m); ok {
+				starts[startKey{family, scopeMetrics.Scope.Name, scopeMetrics.Scope.Version}] = timestamppb.New(start)
+			}
+		}
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := startKey{family: family.GetName()}
+			for _, label := range metric.GetLabel() {
+				switch label.GetName() {
+				case scopeNameLabel:
+					key.scopeName = label.GetValue()
+				case scopeVersionLabel:
+					key.scopeVersion = label.GetValue()
+				}
+			}
+			created, ok := starts[key]
+			if !ok {
+				continue
+			}
+			switch {
+			case metric.Counter != nil && metric.Counter.CreatedTimestamp == nil:
+				metric.Counter.CreatedTimestamp = created
+			case metric.Histogram != nil && metric.Histogram.CreatedTimestamp == nil:
+				metric.Histogram.CreatedTimestamp = created
+			}
+		}
+	}
+	return families, err
+}
+
+const (
+	scopeNameLabel    = "otel_scope_name"
+	scopeVersionLabel = "otel_scope_version"
+	counterSuffix     = "_total"
+)
+
+// unitSuffixes are the suffixes the exporter adds to the names of the
+// metrics for their unit.
+var unitSuffixes = map[string]string{
+	"d":    "_days",
+	"h":    "_hours",
+	"min":  "_minutes",
+	"s":    "_seconds",
+	"ms":   "_milliseconds",
+	"us":   "_microseconds",
+	"ns":   "_nanoseconds",
+	"By":   "_bytes",
+	"KiBy": "_kibibytes",
+	"MiBy": "_mebibytes",
+	"GiBy": "_gibibytes",
+	"TiBy": "_tibibytes",
+	"KBy":  "_kilobytes",
+	"MBy":  "_megabytes",
+	"GBy":  "_gigabytes",
+	"TBy":  "_terabytes",
+	"m":    "_meters",
+	"V":    "_volts",
+	"A":    "_amperes",
+	"J":    "_joules",
+	"W":    "_watts",
+	"g":    "_grams",
+	"Cel":  "_celsius",
+	"Hz":   "_hertz",
+	"1":    "_ratio",
+	"%":    "_percent",
+}
+
+// nameOptions are the options of the exporter changing the names of the
+// metric families.
+type nameOptions struct {
+	withoutUnits           bool
+	withoutCounterSuffixes bool
+}
+
+// start returns the family name the exporter gives to the counter or
+// histogram m, like go.opentelemetry.io/otel/exporters/prometheus does, and
+// the start time of its data points.
+func (o nameOptions) start(m metricdata.Metrics) (string, time.Time, bool) {
+	var start time.Time
+	counter := false
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		if !data.IsMonotonic || len(data.DataPoints) == 0 {
+			return "", start, false
+		}
+		counter, start = true, data.DataPoints[0].StartTime
+	case metricdata.Sum[float64]:
+		if !data.IsMonotonic || len(data.DataPoints) == 0 {
+			return "", start, false
+		}
+		counter, start = true, data.DataPoints[0].StartTime
+	case metricdata.Histogram[int64]:
+		if len(data.DataPoints) == 0 {
+			return "", start, false
+		}
+		start = data.DataPoints[0].StartTime
+	case metricdata.Histogram[float64]:
+		if len(data.DataPoints) == 0 {
+			return "", start, false
+		}
+		start = data.DataPoints[0].StartTime
+	default:
+		return "", start, false
+	}
+
+	name := sanitizeName(m.Name)
+	addCounterSuffix := counter && !o.withoutCounterSuffixes
+	if addCounterSuffix {
+		name = strings.TrimSuffix(name, counterSuffix)
+	}
+	if suffix, ok := unitSuffixes[m.Unit]; ok && !o.withoutUnits && !strings.HasSuffix(name, suffix) {
+		name += suffix
+	}
+	if addCounterSuffix {
+		name += counterSuffix
+	}
+	return name, start, true
+}
+
+// sanitizeName replaces the characters of name which are invalid in a
+// metric name by _, prefixing it with _ when it starts with a digit.
+func sanitizeName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':', r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		case i == 0 && r >= '0' && r <= '9':
+			b.WriteByte('_')
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+)
+
+var ErrNilErrorHandler = errors.New("error handler must not be nil")
+
+// Option customizes the exporters created by the factory of [NewFactory].
+type Option func(*options)
+
+type options struct {
+	errorHandler func(error)
+	registerer   prom.Registerer
+	gatherer     prom.Gatherer
+	registrySet  bool
+}
+
+// WithErrorHandler sets the function receiving the errors of the server
+// exposing the metrics once it is started, otel.Handle by default.
+func WithErrorHandler(handler func(error)) Option {
+	return func(o *options) {
+		o.errorHandler = handler
+	}
+}
+
+// NewFactory returns a factory creating the exporters with opts, to be
+// given to opentelemetry.WithExporterFactory in place of [CreateExporter].
+func NewFactory(opts ...Option) func(context.Context, map[string]interface{}) (interface{}, error) {
+	o := options{errorHandler: otel.Handle}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(ctx context.Context, cfg map[string]interface{}) (interface{}, error) {
+		o := o
+		if o.errorHandler == nil {
+			return nil, ErrNilErrorHandler
+		}
+		if o.registrySet && (o.registerer == nil || o.gatherer == nil) {
+			return nil, ErrNilRegistry
+		}
+		if !o.registrySet {
+			// every exporter has its own registry.
+			registry := prom.NewRegistry()
+			o.registerer, o.gatherer = registry, registry
+		}
+		return createExporter(ctx, cfg, o)
+	}
+}
@@ -6,13 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"go.opentelemetry.io/otel/bridge/opencensus"
+	"net"
 	"net/http"
-	"time"
+	"strings"
 
 	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/transport"
 
 	"github.com/prometheus/client_golang/prometheus/collectors"
-	"github.com/rs/zerolog/log"
 
 	prom "github.com/prometheus/client_golang/prometheus"
 	promhttp "github.com/prometheus/client_golang/prometheus/promhttp"
@@ -20,18 +21,24 @@ import (
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
+var ErrServerSettingsWithoutServe = errors.New("basic_auth and tls require serve to be true")
+var ErrExemplarsWithoutOpenMetrics = errors.New("exemplars require open_metrics to be true")
+var ErrExemplarsDisabled = errors.New("exemplars require the environment variable OTEL_GO_X_EXEMPLAR=true")
+var ErrInvalidPath = errors.New("path must start with /")
+
 const (
 	// ExporterKey is the name for the prometheus exporter
 	ExporterKey    = config.ExporterKind("prometheus")
 	ReadTimeoutMs  = 3000
 	WriteTimeoutMs = 3000
 	LocalPort      = 9091
+	MetricsPath    = "/metrics"
 )
 
 // CollectorConfig has the variables to configure the
 // prometheus exporter.
 type CollectorConfig struct {
-	// Port is the port on which /metrics endpoint will be exposed
+	// Port is the port on which /metrics endpoint will be exposed, 0 for a port chosen by the system
 	Port                    int  `json:"port"`
 	ProcessMetrics          bool `json:"process_metrics"`
 	GoMetrics               bool `json:"go_metrics"`
@@ -39,12 +46,71 @@ type CollectorConfig struct {
 	WriteTimeoutInMillis    *int `json:"write_timeout_in_millis"`
 	OpenCensusBridgeEnabled bool `json:"opencensus_bridge_enabled"`
 	DisableUnitSuffix       bool `json:"disable_unit_suffix"`
+	// OpenMetrics negotiates the OpenMetrics format with the scrapers supporting it
+	OpenMetrics bool `json:"open_metrics"`
+	// Exemplars attaches to the counters and histograms the trace and span IDs of
+	// sampled spans active when recording, it requires OpenMetrics and the
+	// OTEL_GO_X_EXEMPLAR=true environment variable of the SDK
+	Exemplars bool `json:"exemplars"`
+	// CreatedTimestamps exposes when the SDK started accumulating the counters and
+	// histograms, as _created samples with OpenMetrics
+	CreatedTimestamps bool `json:"created_timestamps"`
+	// Serve starts a server exposing the metrics, when false the application
+	// serves the handler of the Collector itself
+	Serve bool `json:"serve"`
+	// Path is the path of the metrics endpoint, starting with /
+	Path string `json:"path"`
+	// ListenAddress is the address of the server (exp: 127.0.0.1:9091), overriding Port
+	ListenAddress string `json:"listen_address"`
+	// BasicAuth protects the server with a username and password
+	BasicAuth *BasicAuthConfig `json:"basic_auth"`
+	// TLS serves the metrics over https
+	TLS *transport.ServerTLSConfig `json:"tls"`
+}
+
+// BasicAuthConfig has the credentials of the basic authentication.
+type BasicAuthConfig struct {
+	Username string `json:"username"`
+	// Password accepts the references resolved by [transport.ResolveValue]
+	Password string `json:"password"`
 }
 
 // Collector implements the metrics exporter
 type Collector struct {
-	registry *prom.Registry
-	exporter *prometheus.Exporter
+	registerer prom.Registerer
+	gatherer   prom.Gatherer
+	exporter   *prometheus.Exporter
+	handler    http.Handler
+	server     *http.Server
+	listener   net.Listener
+}
+
+// Addr returns the address the metrics endpoint is served on, which tells
+// the port chosen by the system when the configured port is 0. It is nil
+// when the exporter does not serve the metrics.
+func (c *Collector) Addr() net.Addr {
+	if c.listener == nil {
+		return nil
+	}
+	return c.listener.Addr()
+}
+
+// Registerer returns the registry of the runtime metrics, to register the
+// collectors of the prometheus client with, e.g. during a migration to
+// OpenTelemetry. It is the one given with [WithRegistry] if any.
+func (c *Collector) Registerer() prom.Registerer {
+	return c.registerer
+}
+
+// Gatherer returns the gatherer of all the metrics exposed by the handler.
+func (c *Collector) Gatherer() prom.Gatherer {
+	return c.gatherer
+}
+
+// Handler returns the handler exposing the metrics, to be mounted on the
+// server of the application when the exporter does not serve them.
+func (c *Collector) Handler() http.Handler {
+	return c.handler
 }
 
 // MetricReader implements the interface to exporte metrics.
@@ -52,6 +118,14 @@ func (c *Collector) MetricReader() sdkmetric.Reader {
 	return c.exporter
 }
 
+// Shutdown gracefully stops the server exposing the metrics endpoint.
+func (c *Collector) Shutdown(ctx context.Context) error {
+	if c.server == nil {
+		return nil
+	}
+	return c.server.Shutdown(ctx)
+}
+
 // ParseConfig creates a Prometheus configuration.
 func ParseConfig(in map[string]interface{}) (*CollectorConfig, error) {
 	defaultReadTimeout := ReadTimeoutMs
@@ -62,38 +136,60 @@ func ParseConfig(in map[string]interface{}) (*CollectorConfig, error) {
 		GoMetrics:            true,
 		ReadTimeoutInMillis:  &defaultReadTimeout,
 		WriteTimeoutInMillis: &defaultWriteTimeout,
+		Serve:                true,
+		Path:                 MetricsPath,
 	}
 	err := config.Parse(in, &defaultCfg)
 	if err != nil {
 		return nil, err
 	}
+	if !strings.HasPrefix(defaultCfg.Path, "/") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPath, defaultCfg.Path)
+	}
+	if !defaultCfg.Serve && (defaultCfg.BasicAuth != nil || defaultCfg.TLS != nil) {
+		return nil, ErrServerSettingsWithoutServe
+	}
+	if defaultCfg.Exemplars && !defaultCfg.OpenMetrics {
+		return nil, ErrExemplarsWithoutOpenMetrics
+	}
+	if defaultCfg.Exemplars && !exemplarsEnabled() {
+		return nil, ErrExemplarsDisabled
+	}
 	return &defaultCfg, nil
 }
 
-// CreateExporter creates a Prometheus exporter instance.
+// CreateExporter creates a Prometheus exporter instance with the default
+// options of [NewFactory].
 func CreateExporter(ctx context.Context, cfg map[string]interface{}) (interface{}, error) {
+	return NewFactory()(ctx, cfg)
+}
+
+func createExporter(_ context.Context, cfg map[string]interface{}, o options) (interface{}, error) {
 	promCfg, err := ParseConfig(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	prometheusRegistry := prom.NewRegistry()
+	registerer, gatherer := o.registerer, o.gatherer
 
 	if promCfg.ProcessMetrics {
-		err := prometheusRegistry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		err := register(registerer, collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if promCfg.GoMetrics {
-		err = prometheusRegistry.Register(collectors.NewGoCollector())
+		err = register(registerer, collectors.NewGoCollector())
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	opts := []prometheus.Option{prometheus.WithRegisterer(prometheusRegistry)}
+	// the metrics of the SDK are in their own registry for their created
+	// timestamps to be set without altering the runtime metrics.
+	otelRegistry := prom.NewRegistry()
+	opts := []prometheus.Option{prometheus.WithRegisterer(otelRegistry)}
 	if promCfg.OpenCensusBridgeEnabled {
 		opts = append(opts, prometheus.WithProducer(opencensus.NewMetricProducer()))
 	}
@@ -105,32 +201,32 @@ func CreateExporter(ctx context.Context, cfg map[string]interface{}) (interface{
 	if err != nil {
 		return nil, err
 	}
-
-	router := http.NewServeMux()
-	router.Handle("/metrics", promhttp.HandlerFor(prometheusRegistry,
-		promhttp.HandlerOpts{}))
-	server := http.Server{
-		Handler:      router,
-		Addr:         fmt.Sprintf(":%d", promCfg.Port),
-		ReadTimeout:  time.Duration(*promCfg.ReadTimeoutInMillis) * time.Millisecond,
-		WriteTimeout: time.Duration(*promCfg.WriteTimeoutInMillis) * time.Millisecond,
+	var otelGatherer prom.Gatherer = otelRegistry
+	if promCfg.CreatedTimestamps {
+		otelGatherer = &createdTimestampGatherer{
+			Gatherer: otelRegistry,
+			reader:   exporter,
+			names: nameOptions{
+				withoutUnits:           promCfg.DisableUnitSuffix,
+				withoutCounterSuffixes: promCfg.DisableUnitSuffix,
+			},
+		}
 	}
 
-	go func() {
-		if serverErr := server.ListenAndServe(); !errors.Is(serverErr, http.ErrServerClosed) {
-			log.Fatal().Str("SERVICE", "prometheus").Msgf("The Prometheus exporter failed to listen and serve: %v", serverErr)
+	collector := &Collector{
+		registerer: registerer,
+		gatherer:   prom.Gatherers{gatherer, otelGatherer},
+		exporter:   exporter,
+	}
+	collector.handler = promhttp.HandlerFor(collector.gatherer, promhttp.HandlerOpts{
+		EnableOpenMetrics:                   promCfg.OpenMetrics,
+		EnableOpenMetricsTextCreatedSamples: promCfg.OpenMetrics && promCfg.CreatedTimestamps,
+	})
+	if promCfg.Serve {
+		err = collector.serve(promCfg, o.errorHandler)
+		if err != nil {
+			return nil, fmt.Errorf("prometheus exporter: %w", err)
 		}
-	}()
-
-	go func() {
-		<-ctx.Done()
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		_ = server.Shutdown(ctx)
-	}()
-
-	return &Collector{
-		registry: prometheusRegistry,
-		exporter: exporter,
-	}, nil
+	}
+	return collector, nil
 }
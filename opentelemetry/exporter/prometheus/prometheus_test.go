@@ -2,10 +2,19 @@ package prometheus
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestConfigFromInterface(t *testing.T) {
@@ -25,6 +34,8 @@ func TestConfigFromInterface(t *testing.T) {
 		WriteTimeoutInMillis:    &duration,
 		DisableUnitSuffix:       false,
 		OpenCensusBridgeEnabled: false,
+		Serve:                   true,
+		Path:                    MetricsPath,
 	}
 	require.Equal(t, expectedConfig, collectorConfig)
 	readTimeout := 1000
@@ -48,23 +59,252 @@ func TestConfigFromInterface(t *testing.T) {
 		WriteTimeoutInMillis:    &writeTimeout,
 		OpenCensusBridgeEnabled: true,
 		DisableUnitSuffix:       true,
+		Serve:                   true,
+		Path:                    MetricsPath,
 	}
 	require.Equal(t, expectedConfig, collectorConfig)
 }
 
+func TestConfigWithInvalidPath(t *testing.T) {
+	tests := map[string]string{
+		"empty":               "",
+		"without leading /":   "metrics",
+		"with relative parts": "./metrics",
+	}
+	for name, path := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseConfig(map[string]interface{}{"path": path})
+			require.ErrorIs(t, err, ErrInvalidPath)
+		})
+	}
+}
+
 func TestExporter(t *testing.T) {
 	cfg := map[string]interface{}{
-		"port":                    9090,
+		"port":                    0,
 		"process_metrics":         true,
 		"go_metrics":              true,
 		"read_timeout_in_millis":  1000,
 		"write_timeout_in_millis": 2000,
 	}
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx := context.Background()
 	exporterInstance, err := CreateExporter(ctx, cfg)
 	require.NoError(t, err)
-	_, ok := exporterInstance.(*Collector)
+	collector, ok := exporterInstance.(*Collector)
 	require.True(t, ok)
-	cancel()
-	time.Sleep(time.Millisecond * 200)
+	require.NoError(t, collector.Shutdown(ctx))
+}
+
+func TestExporterOnRandomPort(t *testing.T) {
+	ctx := context.Background()
+	exporterInstance, err := CreateExporter(ctx, map[string]interface{}{"port": 0})
+	require.NoError(t, err)
+	collector := exporterInstance.(*Collector)
+	defer func() { require.NoError(t, collector.Shutdown(ctx)) }()
+
+	port := collector.Addr().(*net.TCPAddr).Port
+	require.NotZero(t, port)
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", port))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestExporterWithPortInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	_, err = CreateExporter(context.Background(), map[string]interface{}{
+		"port": listener.Addr().(*net.TCPAddr).Port,
+	})
+	require.ErrorContains(t, err, "address already in use")
+}
+
+func TestExporterWithErrorHandler(t *testing.T) {
+	ctx := context.Background()
+	errs := make(chan error, 1)
+	exporterInstance, err := NewFactory(WithErrorHandler(func(err error) { errs <- err }))(ctx, map[string]interface{}{"port": 0})
+	require.NoError(t, err)
+	collector := exporterInstance.(*Collector)
+	defer func() { require.NoError(t, collector.Shutdown(ctx)) }()
+
+	// the server fails once its listener is closed underneath it.
+	require.NoError(t, collector.listener.Close())
+	select {
+	case err := <-errs:
+		require.ErrorContains(t, err, "prometheus exporter")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the error handler was not invoked")
+	}
+
+	_, err = NewFactory(WithErrorHandler(nil))(ctx, map[string]interface{}{"serve": false})
+	require.ErrorIs(t, err, ErrNilErrorHandler)
+}
+
+func TestExporterWithoutServer(t *testing.T) {
+	ctx := context.Background()
+	exporterInstance, err := CreateExporter(ctx, map[string]interface{}{"serve": false})
+	require.NoError(t, err)
+	collector := exporterInstance.(*Collector)
+	require.Nil(t, collector.Addr())
+
+	server := httptest.NewServer(collector.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, collector.Shutdown(ctx))
+
+	_, err = CreateExporter(ctx, map[string]interface{}{
+		"serve":      false,
+		"basic_auth": map[string]interface{}{"username": "prometheus", "password": "secret"},
+	})
+	require.ErrorIs(t, err, ErrServerSettingsWithoutServe)
+}
+
+func TestExporterWithBasicAuth(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("METRICS_PASSWORD", "secret")
+	exporterInstance, err := CreateExporter(ctx, map[string]interface{}{
+		"listen_address": "127.0.0.1:0",
+		"path":           "/internal/metrics",
+		"basic_auth":     map[string]interface{}{"username": "prometheus", "password": "${env:METRICS_PASSWORD}"},
+	})
+	require.NoError(t, err)
+	collector := exporterInstance.(*Collector)
+	defer func() { require.NoError(t, collector.Shutdown(ctx)) }()
+
+	get := func(path, username, password string) int {
+		req, err := http.NewRequest(http.MethodGet, "http://"+collector.Addr().String()+path, nil)
+		require.NoError(t, err)
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusOK, get("/internal/metrics", "prometheus", "secret"))
+	require.Equal(t, http.StatusUnauthorized, get("/internal/metrics", "prometheus", "wrong"))
+	require.Equal(t, http.StatusUnauthorized, get("/internal/metrics", "", ""))
+	require.Equal(t, http.StatusNotFound, get("/metrics", "prometheus", "secret"))
+}
+
+func TestExporterWithOpenMetrics(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(exemplarFeature, "true")
+
+	exporterInstance, err := CreateExporter(ctx, map[string]interface{}{
+		"serve":              false,
+		"open_metrics":       true,
+		"exemplars":          true,
+		"created_timestamps": true,
+	})
+	require.NoError(t, err)
+	collector := exporterInstance.(*Collector)
+
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(collector.MetricReader()))
+	defer func() { require.NoError(t, meterProvider.Shutdown(ctx)) }()
+	tracerProvider := sdktrace.NewTracerProvider()
+	spanCtx, span := tracerProvider.Tracer("test").Start(ctx, "request")
+	histogram, err := meterProvider.Meter("test").Float64Histogram("latency", metric.WithUnit("s"))
+	require.NoError(t, err)
+	histogram.Record(spanCtx, 0.2)
+	span.End()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	resp := httptest.NewRecorder()
+	collector.Handler().ServeHTTP(resp, req)
+	require.Contains(t, resp.Header().Get("Content-Type"), "application/openmetrics-text")
+	body := resp.Body.String()
+	require.Regexp(t, `le="5.0"} 1 # {(trace_id|span_id)=.*} 0.2 `, body)
+	require.Contains(t, body, fmt.Sprintf(`trace_id="%s"`, span.SpanContext().TraceID()))
+	require.Contains(t, body, fmt.Sprintf(`span_id="%s"`, span.SpanContext().SpanID()))
+	require.Contains(t, body, "latency_seconds_created{")
+
+	_, err = CreateExporter(ctx, map[string]interface{}{"serve": false, "exemplars": true})
+	require.ErrorIs(t, err, ErrExemplarsWithoutOpenMetrics)
+
+	t.Setenv(exemplarFeature, "false")
+	_, err = CreateExporter(ctx, map[string]interface{}{"serve": false, "open_metrics": true, "exemplars": true})
+	require.ErrorIs(t, err, ErrExemplarsDisabled)
+}
+
+func TestExporterWithCreatedTimestamps(t *testing.T) {
+	ctx := context.Background()
+	exporterInstance, err := CreateExporter(ctx, map[string]interface{}{"serve": false, "created_timestamps": true})
+	require.NoError(t, err)
+	collector := exporterInstance.(*Collector)
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(collector.MetricReader()))
+	defer func() { require.NoError(t, meterProvider.Shutdown(ctx)) }()
+	meter := meterProvider.Meter("test")
+
+	created := func(name string) time.Time {
+		families, err := collector.gatherer.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+			metric := family.GetMetric()[0]
+			if metric.Counter != nil {
+				return metric.Counter.GetCreatedTimestamp().AsTime()
+			}
+			return metric.Histogram.GetCreatedTimestamp().AsTime()
+		}
+		require.Failf(t, "metric family not found", name)
+		return time.Time{}
+	}
+
+	counter, err := meter.Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+	requestsCreated := created("requests_total")
+	require.False(t, requestsCreated.IsZero())
+
+	// a histogram created later starts later
+	later := time.Now()
+	histogram, err := meter.Float64Histogram("latency", metric.WithUnit("s"))
+	require.NoError(t, err)
+	histogram.Record(ctx, 0.2)
+	require.False(t, created("latency_seconds").Before(later))
+	require.Equal(t, requestsCreated, created("requests_total"))
+}
+
+func TestExporterWithRegistry(t *testing.T) {
+	registry := prom.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector())
+	legacyCounter := prom.NewCounter(prom.CounterOpts{Name: "legacy_requests_total", Help: "Requests."})
+	registry.MustRegister(legacyCounter)
+	legacyCounter.Inc()
+
+	ctx := context.Background()
+	exporterInstance, err := NewFactory(WithRegistry(registry, registry))(ctx, map[string]interface{}{"serve": false})
+	require.NoError(t, err)
+	collector := exporterInstance.(*Collector)
+	require.Same(t, registry, collector.Registerer())
+
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(collector.MetricReader()))
+	defer func() { require.NoError(t, meterProvider.Shutdown(ctx)) }()
+	counter, err := meterProvider.Meter("test").Int64Counter("otel_requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	resp := httptest.NewRecorder()
+	collector.Handler().ServeHTTP(resp, req)
+	body := resp.Body.String()
+	require.Contains(t, body, "legacy_requests_total 1")
+	require.Contains(t, body, `otel_requests_total{otel_scope_name="test",otel_scope_version=""} 2`)
+	require.Contains(t, body, "process_cpu_seconds_total")
+	require.Contains(t, body, "go_goroutines")
+
+	_, err = NewFactory(WithRegistry(registry, nil))(ctx, map[string]interface{}{"serve": false})
+	require.ErrorIs(t, err, ErrNilRegistry)
+	_, err = NewFactory(WithRegistry(nil, registry))(ctx, map[string]interface{}{"serve": false})
+	require.ErrorIs(t, err, ErrNilRegistry)
 }
@@ -0,0 +1,32 @@
+package prometheus
+
+import (
+	"errors"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+var ErrNilRegistry = errors.New("registerer and gatherer must not be nil")
+
+// WithRegistry sets the registry with which the exporters register the
+// runtime metrics, and the gatherer of the metrics exposed along the ones
+// of OpenTelemetry, e.g. prom.DefaultRegisterer and prom.DefaultGatherer
+// for the metrics of promauto. The exporters have their own registry
+// otherwise.
+func WithRegistry(registerer prom.Registerer, gatherer prom.Gatherer) Option {
+	return func(o *options) {
+		o.registerer, o.gatherer = registerer, gatherer
+		o.registrySet = true
+	}
+}
+
+// register registers c with r, unless a collector of the same metrics
+// is already registered, e.g. the runtime collectors of prom.DefaultRegisterer.
+func register(r prom.Registerer, c prom.Collector) error {
+	err := r.Register(c)
+	var alreadyRegistered prom.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+	return err
+}
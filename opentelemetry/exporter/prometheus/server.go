@@ -0,0 +1,78 @@
+package prometheus
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/transport"
+)
+
+// serve starts the server exposing the metrics. The listener is bound
+// before returning for the errors, e.g. a port already in use, to be
+// returned instead of happening in the background. The errors of the
+// server once started are given to errorHandler.
+func (c *Collector) serve(cfg *CollectorConfig, errorHandler func(error)) error {
+	handler := c.handler
+	if cfg.BasicAuth != nil {
+		password, err := transport.ResolveValue(cfg.BasicAuth.Password)
+		if err != nil {
+			return fmt.Errorf("basic_auth password: %w", err)
+		}
+		handler = basicAuth(handler, cfg.BasicAuth.Username, password)
+	}
+	router := http.NewServeMux()
+	router.Handle(cfg.Path, handler)
+	server := &http.Server{
+		Handler:      router,
+		ReadTimeout:  time.Duration(*cfg.ReadTimeoutInMillis) * time.Millisecond,
+		WriteTimeout: time.Duration(*cfg.WriteTimeoutInMillis) * time.Millisecond,
+	}
+
+	address := cfg.ListenAddress
+	if address == "" {
+		address = fmt.Sprintf(":%d", cfg.Port)
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	if cfg.TLS != nil {
+		server.TLSConfig, err = transport.NewServerTLSConfig(*cfg.TLS)
+		if err != nil {
+			return errors.Join(err, listener.Close())
+		}
+	}
+	go func() {
+		var serverErr error
+		if server.TLSConfig != nil {
+			// the certificates are given by the TLSConfig.
+			serverErr = server.ServeTLS(listener, "", "")
+		} else {
+			serverErr = server.Serve(listener)
+		}
+		if !errors.Is(serverErr, http.ErrServerClosed) {
+			errorHandler(fmt.Errorf("prometheus exporter: %w", serverErr))
+		}
+	}()
+	c.server = server
+	c.listener = listener
+	return nil
+}
+
+func basicAuth(next http.Handler, username, password string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(u), []byte(username)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(p), []byte(password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,133 @@
+package remotewrite
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// series is a time series of the remote-write protocol, with a sample.
+type series struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+// timeSeries converts the metric families to series, a series per sample
+// of the exposition format (exp: the _bucket, _sum and _count of the
+// histograms). The external labels are added to the series without a
+// label of the same name.
+//
+// Only the classic buckets of the histograms are sent, the native
+// histograms of the remote-write protocol not being supported: a native
+// histogram is sent as its +Inf bucket, _sum and _count. The SDK
+// exponential histograms are not converted to Prometheus metrics anyway.
+func timeSeries(families []*dto.MetricFamily, externalLabels map[string]string, now time.Time) []series {
+	var out []series
+	for _, family := range families {
+		name := family.GetName()
+		for _, m := range family.GetMetric() {
+			timestamp := now.UnixMilli()
+			if m.TimestampMs != nil {
+				timestamp = m.GetTimestampMs()
+			}
+			add := func(name string, value float64, extra ...label) {
+				labels := make([]label, 0, len(m.GetLabel())+len(extra)+len(externalLabels)+1)
+				labels = append(labels, label{name: "__name__", value: name})
+				present := map[string]bool{}
+				for _, l := range m.GetLabel() {
+					labels = append(labels, label{name: l.GetName(), value: l.GetValue()})
+					present[l.GetName()] = true
+				}
+				for _, l := range extra {
+					labels = append(labels, l)
+					present[l.name] = true
+				}
+				for name, value := range externalLabels {
+					if !present[name] {
+						labels = append(labels, label{name: name, value: value})
+					}
+				}
+				sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+				out = append(out, series{labels: labels, value: value, timestamp: timestamp})
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add(name, m.GetUntyped().GetValue())
+			case dto.MetricType_HISTOGRAM:
+				histogram := m.GetHistogram()
+				infinite := false
+				for _, bucket := range histogram.GetBucket() {
+					infinite = infinite || math.IsInf(bucket.GetUpperBound(), 1)
+					add(name+"_bucket", float64(bucket.GetCumulativeCount()), label{name: "le", value: formatFloat(bucket.GetUpperBound())})
+				}
+				if !infinite {
+					add(name+"_bucket", float64(histogram.GetSampleCount()), label{name: "le", value: "+Inf"})
+				}
+				add(name+"_sum", histogram.GetSampleSum())
+				add(name+"_count", float64(histogram.GetSampleCount()))
+			case dto.MetricType_SUMMARY:
+				summary := m.GetSummary()
+				for _, quantile := range summary.GetQuantile() {
+					add(name, quantile.GetValue(), label{name: "quantile", value: formatFloat(quantile.GetQuantile())})
+				}
+				add(name+"_sum", summary.GetSampleSum())
+				add(name+"_count", float64(summary.GetSampleCount()))
+			}
+		}
+	}
+	return out
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// encodeWriteRequest encodes the series as a prometheus.WriteRequest:
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label { string name = 1; string value = 2; }
+//	message Sample { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(list []series) []byte {
+	var out []byte
+	for _, s := range list {
+		var ts []byte
+		for _, l := range s.labels {
+			var lb []byte
+			lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.name)
+			lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, lb)
+		}
+		var sample []byte
+		sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.value))
+		sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(s.timestamp))
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, sample)
+		out = protowire.AppendTag(out, 1, protowire.BytesType)
+		out = protowire.AppendBytes(out, ts)
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"go.opentelemetry.io/otel"
+)
+
+// run pushes the metrics every interval until the collector is shut down.
+func (c *Collector) run(interval time.Duration) {
+	defer close(c.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			if err := c.push(ctx); err != nil && ctx.Err() == nil {
+				otel.Handle(err)
+			}
+		}
+	}
+}
+
+// push gathers the metrics and sends them in requests of at most
+// maxSamples samples, one push at a time so that the samples of a series
+// are received in order. A failing request does not prevent the others.
+func (c *Collector) push(ctx context.Context) error {
+	c.pushing.Lock()
+	defer c.pushing.Unlock()
+	families, err := c.registry.Gather()
+	if err != nil {
+		return err
+	}
+	series := timeSeries(families, c.client.externalLabels, time.Now())
+	var errs []error
+	for len(series) > 0 {
+		batch := series[:min(len(series), c.client.maxSamples)]
+		series = series[len(batch):]
+		errs = append(errs, c.client.send(ctx, snappy.Encode(nil, encodeWriteRequest(batch))))
+	}
+	return errors.Join(errs...)
+}
+
+// client sends the write requests to the endpoint.
+type client struct {
+	http           *http.Client
+	url            string
+	headers        map[string]string
+	externalLabels map[string]string
+	maxRetries     int
+	backoff        time.Duration
+	maxSamples     int
+}
+
+// retryableError is a failure of a push worth retrying.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// send posts the compressed write request, retrying the network errors,
+// 5xx and 429 statuses with an exponential backoff.
+func (c *client) send(ctx context.Context, body []byte) error {
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.post(ctx, body)
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt >= c.maxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *client) post(ctx context.Context, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range c.headers {
+		request.Header.Set(name, value)
+	}
+	request.Header.Set("Content-Type", "application/x-protobuf")
+	request.Header.Set("Content-Encoding", "snappy")
+	request.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := c.http.Do(request)
+	if err != nil {
+		return &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	err = fmt.Errorf("remote write to %s failed: %s: %s", c.url, resp.Status, bytes.TrimSpace(message))
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return &retryableError{err: err}
+	}
+	return err
+}
@@ -0,0 +1,261 @@
+// Package remotewrite implements an exporter pushing the metrics to the
+// remote-write endpoint of a Prometheus compatible server, for the
+// processes that cannot be scraped.
+package remotewrite
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/transport"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	// ExporterKey is the name for the prometheus remote-write exporter
+	ExporterKey = config.ExporterKind("prometheus_remote_write")
+
+	ExportIntervalMs  = 30000
+	TimeoutMs         = 10000
+	MaxRetries        = 3
+	RetryBackoffMs    = 500
+	MaxSamplesPerSend = 2000
+)
+
+var ErrEndpointMissing = errors.New("endpoint must be an http or https URL")
+var ErrMaxSamplesPerSend = errors.New("max_samples_per_send must be greater than 0")
+var ErrExportInterval = errors.New("export_interval_ms must be greater than 0")
+var ErrTimeout = errors.New("timeout_ms must be greater than 0")
+var ErrRetryBackoff = errors.New("retry_backoff_ms must be greater than 0")
+var ErrAuthConflict = errors.New("basic_auth and bearer_token are mutually exclusive")
+var ErrInvalidLabelName = errors.New("invalid label name")
+
+// labelName is the syntax of the label names, the names starting with __
+// being reserved.
+var labelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// CollectorConfig has the variables to configure the remote-write exporter
+type CollectorConfig struct {
+	// Endpoint is the URL of the remote-write endpoint (exp: http://prometheus:9090/api/v1/write)
+	Endpoint string `json:"endpoint"`
+	// ExportIntervalMs is the interval between two pushes of metrics
+	ExportIntervalMs int `json:"export_interval_ms"`
+	// TimeoutMs is the timeout of a request pushing metrics
+	TimeoutMs int `json:"timeout_ms"`
+	// MaxRetries is the number of retries of a push failing with a network error,
+	// a 5xx or a 429 status
+	MaxRetries int `json:"max_retries"`
+	// RetryBackoffMs is the delay before the first retry, doubled for each next one
+	RetryBackoffMs int `json:"retry_backoff_ms"`
+	// MaxSamplesPerSend is the maximum number of samples of a request, a push
+	// sending the samples in as many requests as needed
+	MaxSamplesPerSend int `json:"max_samples_per_send"`
+	// Headers are sent with every push, their values accept the references resolved by [transport.ResolveHeaders]
+	Headers map[string]string `json:"headers"`
+	// BasicAuth authenticates the pushes with a username and password
+	BasicAuth *BasicAuthConfig `json:"basic_auth"`
+	// BearerToken authenticates the pushes with a token, it accepts the references resolved by [transport.ResolveValue]
+	BearerToken string `json:"bearer_token"`
+	// ExternalLabels are added to all the series, unless they have a label of the same name
+	// (exp: {"job": "billing-cron", "instance": "pod-1"})
+	ExternalLabels map[string]string `json:"external_labels"`
+	// TLS secures the connection to the endpoint, the system CAs are used by default
+	TLS *transport.TLSConfig `json:"tls"`
+	// DisableUnitSuffix removes the unit and _total suffixes from the metric names
+	DisableUnitSuffix bool `json:"disable_unit_suffix"`
+}
+
+// BasicAuthConfig has the credentials of the basic authentication.
+type BasicAuthConfig struct {
+	Username string `json:"username"`
+	// Password accepts the references resolved by [transport.ResolveValue]
+	Password string `json:"password"`
+}
+
+// Collector implements the metrics exporter. The metrics are converted
+// to Prometheus series as for the prometheus exporter, so that they are
+// named the same whether scraped or pushed.
+type Collector struct {
+	reader   *pushReader
+	registry *prom.Registry
+	client   *client
+
+	stop    chan struct{}
+	done    chan struct{}
+	pushing sync.Mutex
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// MetricReader implements the interface to export metrics. Flushing the
+// meter provider pushes the metrics, and shutting it down pushes them a
+// last time.
+func (c *Collector) MetricReader() sdkmetric.Reader {
+	return c.reader
+}
+
+// Shutdown stops the periodic pushes, the reader pushing the metrics a
+// last time when the meter provider is shut down.
+func (c *Collector) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.stop)
+	}
+	c.mu.Unlock()
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ParseConfig creates a remote-write exporter configuration.
+func ParseConfig(in map[string]interface{}) (*CollectorConfig, error) {
+	defaultConfig := CollectorConfig{
+		ExportIntervalMs:  ExportIntervalMs,
+		TimeoutMs:         TimeoutMs,
+		MaxRetries:        MaxRetries,
+		RetryBackoffMs:    RetryBackoffMs,
+		MaxSamplesPerSend: MaxSamplesPerSend,
+	}
+	err := config.Parse(in, &defaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(defaultConfig.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrEndpointMissing, defaultConfig.Endpoint)
+	}
+	if defaultConfig.ExportIntervalMs <= 0 {
+		return nil, ErrExportInterval
+	}
+	if defaultConfig.TimeoutMs <= 0 {
+		return nil, ErrTimeout
+	}
+	if defaultConfig.RetryBackoffMs <= 0 {
+		return nil, ErrRetryBackoff
+	}
+	if defaultConfig.MaxSamplesPerSend <= 0 {
+		return nil, ErrMaxSamplesPerSend
+	}
+	if defaultConfig.BasicAuth != nil && defaultConfig.BearerToken != "" {
+		return nil, ErrAuthConflict
+	}
+	for name := range defaultConfig.ExternalLabels {
+		if !labelName.MatchString(name) || len(name) > 1 && name[:2] == "__" {
+			return nil, fmt.Errorf("external_labels: %w: %s", ErrInvalidLabelName, name)
+		}
+	}
+	return &defaultConfig, nil
+}
+
+// CreateExporter creates a remote-write exporter instance, pushing the
+// metrics every export interval.
+func CreateExporter(_ context.Context, cfg map[string]interface{}) (interface{}, error) {
+	rwCfg, err := ParseConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newClient(rwCfg)
+	if err != nil {
+		return nil, err
+	}
+	registry := prom.NewRegistry()
+	opts := []prometheus.Option{prometheus.WithRegisterer(registry)}
+	if rwCfg.DisableUnitSuffix {
+		opts = append(opts, prometheus.WithoutUnits(), prometheus.WithoutCounterSuffixes())
+	}
+	exporter, err := prometheus.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	collector := &Collector{
+		registry: registry,
+		client:   client,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	collector.reader = &pushReader{Reader: exporter, collector: collector}
+	go collector.run(time.Duration(rwCfg.ExportIntervalMs) * time.Millisecond)
+	return collector, nil
+}
+
+// pushReader pushes the metrics when the meter provider is flushed and
+// before it is shut down.
+type pushReader struct {
+	sdkmetric.Reader
+	collector *Collector
+}
+
+func (r *pushReader) ForceFlush(ctx context.Context) error {
+	return r.collector.push(ctx)
+}
+
+func (r *pushReader) Shutdown(ctx context.Context) error {
+	err := r.collector.push(ctx)
+	return errors.Join(err, r.Reader.Shutdown(ctx))
+}
+
+// newClient creates the client of the endpoint with the resolved headers.
+func newClient(cfg *CollectorConfig) (*client, error) {
+	headers, err := transport.ResolveHeaders(cfg.Headers)
+	if err != nil {
+		return nil, err
+	}
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	if cfg.BasicAuth != nil {
+		password, err := transport.ResolveValue(cfg.BasicAuth.Password)
+		if err != nil {
+			return nil, fmt.Errorf("basic_auth: %w", err)
+		}
+		credentials := base64.StdEncoding.EncodeToString([]byte(cfg.BasicAuth.Username + ":" + password))
+		headers["Authorization"] = "Basic " + credentials
+	}
+	if cfg.BearerToken != "" {
+		token, err := transport.ResolveValue(cfg.BearerToken)
+		if err != nil {
+			return nil, fmt.Errorf("bearer_token: %w", err)
+		}
+		headers["Authorization"] = "Bearer " + token
+	}
+	httpTransport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if cfg.TLS != nil {
+		endpoint, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		httpTransport.TLSClientConfig, err = transport.ClientTLSConfig(*cfg.TLS, endpoint.Hostname())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &client{
+		http: &http.Client{
+			Timeout:   time.Duration(cfg.TimeoutMs) * time.Millisecond,
+			Transport: httpTransport,
+		},
+		url:            cfg.Endpoint,
+		headers:        headers,
+		externalLabels: cfg.ExternalLabels,
+		maxRetries:     cfg.MaxRetries,
+		backoff:        time.Duration(cfg.RetryBackoffMs) * time.Millisecond,
+		maxSamples:     cfg.MaxSamplesPerSend,
+	}, nil
+}
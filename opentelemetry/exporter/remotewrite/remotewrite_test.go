@@ -0,0 +1,406 @@
+package remotewrite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// receiver is a stand-in of a remote-write endpoint, decoding the series
+// of the requests after answering with the statuses in order. The errors
+// of the handler are checked by the test once it ends.
+type receiver struct {
+	*httptest.Server
+	t *testing.T
+
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	series   [][]series
+	errs     []error
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{t: t, statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.handle))
+	t.Cleanup(func() {
+		r.Close()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		require.Empty(t, r.errs)
+	})
+	return r
+}
+
+func (r *receiver) handle(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+		if status != http.StatusNoContent {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+	}
+	list, err := readWriteRequest(req.Body)
+	if err != nil {
+		r.errs = append(r.errs, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.series = append(r.series, list)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *receiver) received() ([]*http.Request, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests, len(r.series)
+}
+
+// samples returns the samples of the last request by series, the labels
+// other than __name__ formatted as name{label="value",...}.
+func (r *receiver) samples() map[string]float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	require.NotEmpty(r.t, r.series)
+	out := map[string]float64{}
+	for _, s := range r.series[len(r.series)-1] {
+		var name string
+		var labels []string
+		for _, l := range s.labels {
+			if l.name == "__name__" {
+				name = l.value
+				continue
+			}
+			labels = append(labels, l.name+`="`+l.value+`"`)
+		}
+		require.True(r.t, sort.SliceIsSorted(s.labels, func(i, j int) bool { return s.labels[i].name < s.labels[j].name }))
+		out[name+"{"+strings.Join(labels, ",")+"}"] = s.value
+	}
+	return out
+}
+
+func readWriteRequest(body io.Reader) ([]series, error) {
+	compressed, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	b, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWriteRequest(b)
+}
+
+// decodeWriteRequest decodes the series of a prometheus.WriteRequest, its
+// encoding being checked against the one of prompb by TestEncodeWriteRequest.
+func decodeWriteRequest(b []byte) ([]series, error) {
+	var out []series
+	err := fields(b, func(_ protowire.Number, ts []byte) error {
+		var s series
+		err := fields(ts, func(num protowire.Number, value []byte) error {
+			switch num {
+			case 1:
+				var l label
+				err := fields(value, func(num protowire.Number, value []byte) error {
+					if num == 1 {
+						l.name = string(value)
+					} else {
+						l.value = string(value)
+					}
+					return nil
+				})
+				s.labels = append(s.labels, l)
+				return err
+			case 2:
+				return decodeSample(value, &s)
+			}
+			return nil
+		})
+		out = append(out, s)
+		return err
+	})
+	return out, err
+}
+
+func decodeSample(b []byte, s *series) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			s.value = math.Float64frombits(v)
+			b = b[n:]
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			s.timestamp = int64(v)
+			b = b[n:]
+		default:
+			return fmt.Errorf("unexpected field %d of type %d in a sample", num, typ)
+		}
+	}
+	return nil
+}
+
+// fields calls f with the bytes fields of the message b.
+func fields(b []byte, f func(protowire.Number, []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		if typ != protowire.BytesType {
+			return fmt.Errorf("unexpected type %d of field %d", typ, num)
+		}
+		b = b[n:]
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := f(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TestEncodeWriteRequest compares the encoding with the one of the
+// WriteRequest of github.com/prometheus/prometheus/prompb, in the golden
+// file, for the same series.
+func TestEncodeWriteRequest(t *testing.T) {
+	list := []series{
+		{
+			labels:    []label{{"__name__", "requests_total"}, {"job", "billing"}, {"route", "/orders"}},
+			value:     3,
+			timestamp: 1700000000000,
+		},
+		{
+			labels:    []label{{"__name__", "temperature"}, {"le", "+Inf"}},
+			value:     -2.5,
+			timestamp: 1700000000123,
+		},
+	}
+	golden, err := os.ReadFile(filepath.Join("testdata", "write_request.pb"))
+	require.NoError(t, err)
+	require.Equal(t, golden, encodeWriteRequest(list))
+
+	decoded, err := decodeWriteRequest(golden)
+	require.NoError(t, err)
+	require.Equal(t, list, decoded)
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig(map[string]interface{}{"endpoint": "http://prometheus:9090/api/v1/write"})
+	require.NoError(t, err)
+	require.Equal(t, &CollectorConfig{
+		Endpoint:          "http://prometheus:9090/api/v1/write",
+		ExportIntervalMs:  ExportIntervalMs,
+		TimeoutMs:         TimeoutMs,
+		MaxRetries:        MaxRetries,
+		RetryBackoffMs:    RetryBackoffMs,
+		MaxSamplesPerSend: MaxSamplesPerSend,
+	}, cfg)
+
+	_, err = ParseConfig(map[string]interface{}{})
+	require.ErrorIs(t, err, ErrEndpointMissing)
+	_, err = ParseConfig(map[string]interface{}{
+		"endpoint":     "http://prometheus:9090/api/v1/write",
+		"basic_auth":   map[string]interface{}{"username": "user", "password": "secret"},
+		"bearer_token": "token",
+	})
+	require.ErrorIs(t, err, ErrAuthConflict)
+	_, err = ParseConfig(map[string]interface{}{
+		"endpoint":        "http://prometheus:9090/api/v1/write",
+		"external_labels": map[string]string{"__name__": "x"},
+	})
+	require.ErrorIs(t, err, ErrInvalidLabelName)
+
+	for field, want := range map[string]error{
+		"export_interval_ms":   ErrExportInterval,
+		"timeout_ms":           ErrTimeout,
+		"retry_backoff_ms":     ErrRetryBackoff,
+		"max_samples_per_send": ErrMaxSamplesPerSend,
+	} {
+		for _, value := range []int{0, -1} {
+			_, err = ParseConfig(map[string]interface{}{
+				"endpoint": "http://prometheus:9090/api/v1/write",
+				field:      value,
+			})
+			require.ErrorIs(t, err, want, "%s: %d", field, value)
+		}
+	}
+}
+
+func newMeterProvider(t *testing.T, cfg map[string]interface{}) (*Collector, *sdkmetric.MeterProvider) {
+	instance, err := CreateExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	collector := instance.(*Collector)
+	t.Cleanup(func() {
+		require.NoError(t, collector.Shutdown(context.Background()))
+	})
+	return collector, sdkmetric.NewMeterProvider(sdkmetric.WithReader(collector.MetricReader()))
+}
+
+func TestExporter(t *testing.T) {
+	ctx := context.Background()
+	receiver := newReceiver(t)
+	t.Setenv("REMOTE_WRITE_PASSWORD", "secret")
+	_, meterProvider := newMeterProvider(t, map[string]interface{}{
+		"endpoint":        receiver.URL + "/api/v1/write",
+		"basic_auth":      map[string]interface{}{"username": "cron", "password": "${env:REMOTE_WRITE_PASSWORD}"},
+		"external_labels": map[string]string{"job": "billing", "route": "ignored"},
+	})
+	meter := meterProvider.Meter("test")
+	counter, err := meter.Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 3, metric.WithAttributes(attribute.String("route", "/orders")))
+	histogram, err := meter.Float64Histogram("latency", metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(0.1, 1))
+	require.NoError(t, err)
+	histogram.Record(ctx, 0.5)
+
+	require.NoError(t, meterProvider.ForceFlush(ctx))
+	requests, _ := receiver.received()
+	request := requests[0]
+	require.Equal(t, "/api/v1/write", request.URL.Path)
+	require.Equal(t, "application/x-protobuf", request.Header.Get("Content-Type"))
+	require.Equal(t, "snappy", request.Header.Get("Content-Encoding"))
+	require.Equal(t, "0.1.0", request.Header.Get("X-Prometheus-Remote-Write-Version"))
+	username, password, ok := request.BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "cron", username)
+	require.Equal(t, "secret", password)
+
+	samples := receiver.samples()
+	require.Equal(t, 3.0, samples[`requests_total{job="billing",otel_scope_name="test",otel_scope_version="",route="/orders"}`])
+	require.Equal(t, 0.0, samples[`latency_seconds_bucket{job="billing",le="0.1",otel_scope_name="test",otel_scope_version="",route="ignored"}`])
+	require.Equal(t, 1.0, samples[`latency_seconds_bucket{job="billing",le="1",otel_scope_name="test",otel_scope_version="",route="ignored"}`])
+	require.Equal(t, 1.0, samples[`latency_seconds_bucket{job="billing",le="+Inf",otel_scope_name="test",otel_scope_version="",route="ignored"}`])
+	require.Equal(t, 0.5, samples[`latency_seconds_sum{job="billing",otel_scope_name="test",otel_scope_version="",route="ignored"}`])
+	require.Equal(t, 1.0, samples[`latency_seconds_count{job="billing",otel_scope_name="test",otel_scope_version="",route="ignored"}`])
+
+	// the metrics are pushed a last time on shutdown.
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("route", "/orders")))
+	require.NoError(t, meterProvider.Shutdown(ctx))
+	_, pushes := receiver.received()
+	require.Equal(t, 2, pushes)
+	require.Equal(t, 4.0, receiver.samples()[`requests_total{job="billing",otel_scope_name="test",otel_scope_version="",route="/orders"}`])
+}
+
+func TestExporterRetries(t *testing.T) {
+	ctx := context.Background()
+	receiver := newReceiver(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent)
+	_, meterProvider := newMeterProvider(t, map[string]interface{}{
+		"endpoint":         receiver.URL,
+		"bearer_token":     "token",
+		"retry_backoff_ms": 1,
+	})
+	counter, err := meterProvider.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	require.NoError(t, meterProvider.ForceFlush(ctx))
+	requests, pushes := receiver.received()
+	require.Len(t, requests, 3)
+	require.Equal(t, "Bearer token", requests[2].Header.Get("Authorization"))
+	require.Equal(t, 1, pushes)
+}
+
+func TestExporterDoesNotRetryClientErrors(t *testing.T) {
+	ctx := context.Background()
+	receiver := newReceiver(t, http.StatusBadRequest, http.StatusBadRequest)
+	_, meterProvider := newMeterProvider(t, map[string]interface{}{
+		"endpoint":         receiver.URL,
+		"max_retries":      1,
+		"retry_backoff_ms": 1,
+	})
+	counter, err := meterProvider.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	err = meterProvider.ForceFlush(ctx)
+	require.ErrorContains(t, err, "400 Bad Request: Bad Request")
+	requests, _ := receiver.received()
+	require.Len(t, requests, 1)
+}
+
+func TestExporterSplitsPushes(t *testing.T) {
+	ctx := context.Background()
+	receiver := newReceiver(t)
+	_, meterProvider := newMeterProvider(t, map[string]interface{}{
+		"endpoint":             receiver.URL,
+		"max_samples_per_send": 2,
+	})
+	counter, err := meterProvider.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	for _, route := range []string{"/orders", "/payments", "/refunds"} {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.String("route", route)))
+	}
+
+	require.NoError(t, meterProvider.ForceFlush(ctx))
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	routes := map[string]bool{}
+	for _, list := range receiver.series {
+		require.LessOrEqual(t, len(list), 2)
+		for _, s := range list {
+			for _, l := range s.labels {
+				if l.name == "route" {
+					routes[l.value] = true
+				}
+			}
+		}
+	}
+	require.Greater(t, len(receiver.series), 1)
+	require.Len(t, routes, 3)
+
+	_, err = ParseConfig(map[string]interface{}{"endpoint": receiver.URL, "max_samples_per_send": 0})
+	require.ErrorIs(t, err, ErrMaxSamplesPerSend)
+}
+
+func TestExporterPushesPeriodically(t *testing.T) {
+	receiver := newReceiver(t)
+	_, meterProvider := newMeterProvider(t, map[string]interface{}{
+		"endpoint":           receiver.URL,
+		"export_interval_ms": 10,
+	})
+	counter, err := meterProvider.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 1)
+
+	require.Eventually(t, func() bool {
+		_, pushes := receiver.received()
+		return pushes > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,151 @@
+package zipkin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// zipkinSpan is a span of the Zipkin v2 API.
+type zipkinSpan struct {
+	TraceID        string            `json:"traceId"`
+	ID             string            `json:"id"`
+	ParentID       string            `json:"parentId,omitempty"`
+	Name           string            `json:"name"`
+	Kind           string            `json:"kind,omitempty"`
+	Timestamp      int64             `json:"timestamp"`
+	Duration       int64             `json:"duration"`
+	LocalEndpoint  *zipkinEndpoint   `json:"localEndpoint,omitempty"`
+	RemoteEndpoint *zipkinEndpoint   `json:"remoteEndpoint,omitempty"`
+	Annotations    []zipkinEvent     `json:"annotations,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+	IPv4        string `json:"ipv4,omitempty"`
+	IPv6        string `json:"ipv6,omitempty"`
+	Port        int    `json:"port,omitempty"`
+}
+
+type zipkinEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// zipkinKinds are the Zipkin kinds of the span kinds, the internal spans
+// having none.
+var zipkinKinds = map[trace.SpanKind]string{
+	trace.SpanKindServer:   "SERVER",
+	trace.SpanKindClient:   "CLIENT",
+	trace.SpanKindProducer: "PRODUCER",
+	trace.SpanKindConsumer: "CONSUMER",
+}
+
+// model converts span to Zipkin. The attributes become tags, the events
+// annotations and the status the otel.status_code and error tags.
+func (e *spanExporter) model(span sdktrace.ReadOnlySpan) zipkinSpan {
+	spanAttrs := attribute.NewSet(span.Attributes()...)
+	out := zipkinSpan{
+		TraceID:       span.SpanContext().TraceID().String(),
+		ID:            span.SpanContext().SpanID().String(),
+		Name:          span.Name(),
+		Kind:          zipkinKinds[span.SpanKind()],
+		Timestamp:     span.StartTime().UnixMicro(),
+		Duration:      span.EndTime().Sub(span.StartTime()).Microseconds(),
+		LocalEndpoint: endpoint(e.localEndpoint, &spanAttrs, span.Resource().Set()),
+		Tags:          map[string]string{},
+	}
+	if span.Parent().IsValid() {
+		out.ParentID = span.Parent().SpanID().String()
+	}
+	// the internal spans have no peer.
+	if out.Kind != "" {
+		out.RemoteEndpoint = endpoint(e.remoteEndpoint, &spanAttrs)
+	}
+	for _, attr := range span.Attributes() {
+		out.Tags[string(attr.Key)] = attr.Value.Emit()
+	}
+	if scope := span.InstrumentationScope(); scope.Name != "" {
+		out.Tags["otel.scope.name"] = scope.Name
+		if scope.Version != "" {
+			out.Tags["otel.scope.version"] = scope.Version
+		}
+	}
+	switch span.Status().Code {
+	case codes.Error:
+		out.Tags["otel.status_code"] = "ERROR"
+		out.Tags["error"] = span.Status().Description
+	case codes.Ok:
+		out.Tags["otel.status_code"] = "OK"
+	}
+	if len(out.Tags) == 0 {
+		out.Tags = nil
+	}
+	for _, event := range span.Events() {
+		out.Annotations = append(out.Annotations, zipkinEvent{
+			Timestamp: event.Time.UnixMicro(),
+			Value:     eventValue(event),
+		})
+	}
+	return out
+}
+
+// endpoint builds the endpoint from the first attributes of cfg present in
+// the sets, nil if none is.
+func endpoint(cfg EndpointConfig, sets ...*attribute.Set) *zipkinEndpoint {
+	lookup := func(keys []string) (attribute.Value, bool) {
+		for _, key := range keys {
+			for _, set := range sets {
+				if value, ok := set.Value(attribute.Key(key)); ok && value.Emit() != "" {
+					return value, true
+				}
+			}
+		}
+		return attribute.Value{}, false
+	}
+	out := zipkinEndpoint{}
+	if value, ok := lookup(cfg.ServiceName); ok {
+		out.ServiceName = value.Emit()
+	}
+	if value, ok := lookup(cfg.IP); ok {
+		ip := net.ParseIP(value.Emit())
+		if ip.To4() != nil {
+			out.IPv4 = ip.String()
+		} else if ip != nil {
+			out.IPv6 = ip.String()
+		}
+	}
+	if value, ok := lookup(cfg.Port); ok {
+		port, err := strconv.Atoi(value.Emit())
+		if err == nil && port > 0 && port <= 65535 {
+			out.Port = port
+		}
+	}
+	if out == (zipkinEndpoint{}) {
+		return nil
+	}
+	return &out
+}
+
+// eventValue is the name of the event, followed by its attributes in JSON.
+func eventValue(event sdktrace.Event) string {
+	if len(event.Attributes) == 0 {
+		return event.Name
+	}
+	attrs := make(map[string]interface{}, len(event.Attributes))
+	for _, attr := range event.Attributes {
+		attrs[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	b, err := json.Marshal(attrs)
+	if err != nil {
+		return event.Name
+	}
+	return fmt.Sprintf("%s: %s", event.Name, b)
+}
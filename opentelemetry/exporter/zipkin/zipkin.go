@@ -0,0 +1,181 @@
+// Package zipkin implements the Zipkin exporter, sending the spans to the
+// v2 HTTP API of a Zipkin server.
+package zipkin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/internal/transport"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// ExporterKey is the name for the zipkin exporter
+	ExporterKey = config.ExporterKind("zipkin")
+
+	Endpoint  = "http://localhost:9411/api/v2/spans"
+	TimeoutMs = 10000
+)
+
+var ErrInvalidEndpoint = errors.New("endpoint must be an http or https URL")
+
+// CollectorConfig has the variables to configure the zipkin exporter
+type CollectorConfig struct {
+	// Endpoint is the URL of the spans API of the Zipkin server
+	Endpoint string `json:"endpoint"`
+	// TimeoutMs is the timeout of a request sending spans
+	TimeoutMs int `json:"timeout_ms"`
+	// Headers are sent with every request, their values accept the references resolved by [transport.ResolveHeaders]
+	Headers map[string]string `json:"headers"`
+	// LocalEndpoint lists the attributes describing the service recording the spans,
+	// looked up in the span then in the resource attributes
+	LocalEndpoint EndpointConfig `json:"local_endpoint"`
+	// RemoteEndpoint lists the span attributes describing the peer of client and server spans
+	RemoteEndpoint EndpointConfig `json:"remote_endpoint"`
+}
+
+// EndpointConfig lists the attributes of each field of a Zipkin endpoint,
+// the first attribute present being used.
+type EndpointConfig struct {
+	// ServiceName are the attributes of the service name (exp: peer.service)
+	ServiceName []string `json:"service_name"`
+	// IP are the attributes of the IPv4 or IPv6 address (exp: network.peer.address)
+	IP []string `json:"ip"`
+	// Port are the attributes of the port (exp: network.peer.port)
+	Port []string `json:"port"`
+}
+
+// Collector implements the traces exporter.
+type Collector struct {
+	exporter *spanExporter
+}
+
+// SpanExporter implements the interface to export traces.
+func (c *Collector) SpanExporter() sdktrace.SpanExporter {
+	return c.exporter
+}
+
+// Shutdown closes the connections to the Zipkin server.
+func (c *Collector) Shutdown(ctx context.Context) error {
+	return c.exporter.Shutdown(ctx)
+}
+
+// ParseConfig creates a zipkin exporter configuration, the endpoints
+// default to the attributes of the OpenTelemetry semantic conventions.
+func ParseConfig(in map[string]interface{}) (*CollectorConfig, error) {
+	defaultConfig := CollectorConfig{
+		Endpoint:  Endpoint,
+		TimeoutMs: TimeoutMs,
+		LocalEndpoint: EndpointConfig{
+			ServiceName: []string{"service.name"},
+			IP:          []string{"network.local.address", "host.ip"},
+			Port:        []string{"network.local.port"},
+		},
+		RemoteEndpoint: EndpointConfig{
+			ServiceName: []string{"peer.service", "server.address", "net.peer.name", "db.system", "messaging.system", "rpc.service"},
+			IP:          []string{"network.peer.address", "net.sock.peer.addr", "net.peer.ip"},
+			Port:        []string{"network.peer.port", "server.port", "net.peer.port"},
+		},
+	}
+	err := config.Parse(in, &defaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(defaultConfig.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEndpoint, defaultConfig.Endpoint)
+	}
+	return &defaultConfig, nil
+}
+
+// CreateExporter creates a zipkin exporter instance.
+func CreateExporter(_ context.Context, cfg map[string]interface{}) (interface{}, error) {
+	zipkinCfg, err := ParseConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := transport.ResolveHeaders(zipkinCfg.Headers)
+	if err != nil {
+		return nil, err
+	}
+	return &Collector{
+		exporter: &spanExporter{
+			client: &http.Client{
+				Timeout:   time.Duration(zipkinCfg.TimeoutMs) * time.Millisecond,
+				Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+			},
+			url:            zipkinCfg.Endpoint,
+			headers:        headers,
+			localEndpoint:  zipkinCfg.LocalEndpoint,
+			remoteEndpoint: zipkinCfg.RemoteEndpoint,
+		},
+	}, nil
+}
+
+// spanExporter posts the spans in the Zipkin v2 JSON encoding.
+type spanExporter struct {
+	client         *http.Client
+	url            string
+	headers        map[string]string
+	localEndpoint  EndpointConfig
+	remoteEndpoint EndpointConfig
+
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	// the lock is not held during the request, for Shutdown not to wait
+	// for it.
+	e.mu.RLock()
+	stopped := e.stopped
+	e.mu.RUnlock()
+	if stopped || len(spans) == 0 {
+		return nil
+	}
+	models := make([]zipkinSpan, 0, len(spans))
+	for _, span := range spans {
+		models = append(models, e.model(span))
+	}
+	body, err := json.Marshal(models)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range e.headers {
+		request.Header.Set(name, value)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("zipkin export to %s failed: %s", e.url, resp.Status)
+	}
+	return nil
+}
+
+func (e *spanExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	e.stopped = true
+	e.mu.Unlock()
+	e.client.CloseIdleConnections()
+	return ctx.Err()
+}
@@ -0,0 +1,166 @@
+package zipkin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig(map[string]interface{}{
+		"remote_endpoint": map[string]interface{}{"service_name": []string{"peer.hostname"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Endpoint, cfg.Endpoint)
+	require.Equal(t, TimeoutMs, cfg.TimeoutMs)
+	require.Equal(t, []string{"peer.hostname"}, cfg.RemoteEndpoint.ServiceName)
+	require.Equal(t, []string{"network.peer.port", "server.port", "net.peer.port"}, cfg.RemoteEndpoint.Port, "the other fields keep their default")
+	require.Equal(t, []string{"service.name"}, cfg.LocalEndpoint.ServiceName)
+
+	_, err = ParseConfig(map[string]interface{}{"endpoint": "localhost:9411"})
+	require.ErrorIs(t, err, ErrInvalidEndpoint)
+}
+
+func TestExporter(t *testing.T) {
+	ctx := context.Background()
+	requests := make(chan []zipkinSpan, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v2/spans", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		var spans []zipkinSpan
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&spans))
+		requests <- spans
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	instance, err := CreateExporter(ctx, map[string]interface{}{
+		"endpoint": server.URL + "/api/v2/spans",
+		"headers":  map[string]string{"X-Api-Key": "secret"},
+	})
+	require.NoError(t, err)
+	collector := instance.(*Collector)
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(collector.SpanExporter()),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "checkout"), attribute.String("host.ip", "10.0.0.1"))),
+	)
+	tracer := tracerProvider.Tracer("test", trace.WithInstrumentationVersion("1.0.0"))
+
+	ctx, parent := tracer.Start(ctx, "parent")
+	_, span := tracer.Start(ctx, "GET", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("peer.service", "orders"),
+		attribute.String("network.peer.address", "2001:db8::1"),
+		attribute.Int("network.peer.port", 8080),
+		attribute.Int("http.status_code", 500),
+	))
+	span.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", 2)))
+	span.SetStatus(codes.Error, "internal error")
+	span.End()
+
+	spans := <-requests
+	require.Len(t, spans, 1)
+	got := spans[0]
+	require.Equal(t, span.SpanContext().TraceID().String(), got.TraceID)
+	require.Equal(t, span.SpanContext().SpanID().String(), got.ID)
+	require.Equal(t, parent.SpanContext().SpanID().String(), got.ParentID)
+	require.Equal(t, "GET", got.Name)
+	require.Equal(t, "CLIENT", got.Kind)
+	require.Equal(t, &zipkinEndpoint{ServiceName: "checkout", IPv4: "10.0.0.1"}, got.LocalEndpoint)
+	require.Equal(t, &zipkinEndpoint{ServiceName: "orders", IPv6: "2001:db8::1", Port: 8080}, got.RemoteEndpoint)
+	require.Len(t, got.Annotations, 1)
+	require.Equal(t, `retry: {"attempt":2}`, got.Annotations[0].Value)
+	require.Equal(t, map[string]string{
+		"peer.service":         "orders",
+		"network.peer.address": "2001:db8::1",
+		"network.peer.port":    "8080",
+		"http.status_code":     "500",
+		"otel.scope.name":      "test",
+		"otel.scope.version":   "1.0.0",
+		"otel.status_code":     "ERROR",
+		"error":                "internal error",
+	}, got.Tags)
+
+	parent.End()
+	spans = <-requests
+	require.Equal(t, "", spans[0].Kind)
+	require.Nil(t, spans[0].RemoteEndpoint)
+
+	require.NoError(t, tracerProvider.Shutdown(context.Background()))
+	require.NoError(t, collector.Shutdown(context.Background()))
+}
+
+func TestExporterWithMapping(t *testing.T) {
+	exporter := &spanExporter{
+		localEndpoint:  EndpointConfig{ServiceName: []string{"component"}},
+		remoteEndpoint: EndpointConfig{ServiceName: []string{"peer.hostname"}, Port: []string{"peer.port"}},
+	}
+	stub := spanStub(trace.SpanKindServer, attribute.String("component", "gateway"), attribute.String("peer.hostname", "mobile"), attribute.String("peer.port", "443"))
+	got := exporter.model(stub)
+	require.Equal(t, &zipkinEndpoint{ServiceName: "gateway"}, got.LocalEndpoint)
+	require.Equal(t, &zipkinEndpoint{ServiceName: "mobile", Port: 443}, got.RemoteEndpoint)
+}
+
+func TestExporterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	instance, err := CreateExporter(context.Background(), map[string]interface{}{"endpoint": server.URL})
+	require.NoError(t, err)
+	exporter := instance.(*Collector).SpanExporter()
+	err = exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{spanStub(trace.SpanKindInternal)})
+	require.ErrorContains(t, err, "400 Bad Request")
+}
+
+func TestShutdownDuringExport(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(received)
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	instance, err := CreateExporter(context.Background(), map[string]interface{}{"endpoint": server.URL})
+	require.NoError(t, err)
+	exporter := instance.(*Collector).SpanExporter()
+	exported := make(chan error, 1)
+	go func() {
+		exported <- exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{spanStub(trace.SpanKindInternal)})
+	}()
+	<-received
+
+	// the shutdown does not wait for the request in flight.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, exporter.Shutdown(ctx))
+	require.NoError(t, exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{spanStub(trace.SpanKindInternal)}))
+	release <- struct{}{}
+	require.NoError(t, <-exported)
+}
+
+func spanStub(kind trace.SpanKind, attrs ...attribute.KeyValue) sdktrace.ReadOnlySpan {
+	return tracetest.SpanStub{
+		Name: "span",
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1},
+			SpanID:  trace.SpanID{1},
+		}),
+		SpanKind:   kind,
+		Attributes: attrs,
+		Resource:   resource.Empty(),
+	}.Snapshot()
+}
@@ -8,116 +8,304 @@ import (
 
 	"github.com/razorpay/golib/opentelemetry/config"
 	"github.com/razorpay/golib/opentelemetry/exporter"
+	"github.com/razorpay/golib/opentelemetry/propagator"
+	"github.com/razorpay/golib/opentelemetry/sampler"
 
-	"go.opentelemetry.io/otel/metric"
-	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	"github.com/rs/zerolog/log"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
-	"go.opentelemetry.io/otel/trace"
-	nooptrace "go.opentelemetry.io/otel/trace/noop"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/log/global"
 )
 
+// shutdownTimeout bounds the shutdown triggered by the cancellation of the
+// context given to [Register].
+const shutdownTimeout = 5 * time.Second
+
 // Register all the known exporter factories (Opentelemetry, prometheus, etc.)
 // and uses the provided Config to instantiate the configured exporters.
-func Register(ctx context.Context, cfg *config.Config, views []sdkmetric.View) error {
-	err := config.Validate(cfg)
+//
+// Providers and exporters are shut down once ctx is done. Use [Setup] to
+// control the shutdown explicitly.
+func Register(ctx context.Context, cfg *config.Config, views []sdkmetric.View, opts ...SetupOption) error {
+	telemetry, err := Setup(ctx, cfg, views, opts...)
 	if err != nil {
 		return err
 	}
-	exporter.RegisterKnownFactories()
+	go func() {
+		<-ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = telemetry.Shutdown(ctx)
+	}()
+	return nil
+}
 
-	metricExporters, spanExporters, errs := exporter.CreateInstances(ctx, cfg.Exporters)
-	if len(errs) > 0 {
-		return errors.Join(errs...)
+// SetupOption customizes [Setup] and [Register].
+type SetupOption func(*setupOptions)
+
+type setupOptions struct {
+	factories map[config.ExporterKind]exporter.Factory
+}
+
+// WithExporterFactory creates the exporters of kind with factory instead of
+// the registered one, for this setup only, e.g. to give options to the
+// exporters of a package:
+//
+//	opentelemetry.WithExporterFactory(prometheus.ExporterKey, prometheus.NewFactory(prometheus.WithErrorHandler(handle)))
+func WithExporterFactory(kind config.ExporterKind, factory exporter.Factory) SetupOption {
+	return func(o *setupOptions) {
+		o.factories[kind] = factory
 	}
+}
 
-	prop := propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	)
+// Setup behaves like [Register] but does not tie the lifetime of the
+// providers to ctx: the returned [Telemetry] must be shut down by the caller.
+// A disabled cfg results in a [Telemetry] without providers.
+func Setup(ctx context.Context, cfg *config.Config, views []sdkmetric.View, opts ...SetupOption) (*Telemetry, error) {
+	o := setupOptions{factories: map[config.ExporterKind]exporter.Factory{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if cfg != nil && cfg.Disabled {
+		return &Telemetry{}, nil
+	}
+	err := config.Validate(cfg)
+	if err != nil {
+		return nil, err
+	}
+	prop, err := propagator.New(cfg.Propagators)
+	if err != nil {
+		return nil, err
+	}
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	// if we do not have any metrics exporter config but exporters to use, we default
-	// to report to all configured exporters.
-	if cfg.Metrics != nil && cfg.Metrics.Exporters == nil {
-		cfg.Metrics = &config.MetricsConfig{
-			Exporters: make([]string, 0, len(metricExporters)),
-		}
-		exporters := cfg.Metrics.Exporters
-		for metricExporter := range metricExporters {
-			exporters = append(exporters, metricExporter)
-		}
+	metricExporters, spanExporters, logExporters, errs := exporter.CreateInstancesWithFactories(ctx, cfg.Exporters, o.factories)
+	telemetry := &Telemetry{
+		exporters: shutdowners(metricExporters, spanExporters, logExporters),
+		instances: instances(metricExporters, spanExporters, logExporters),
+	}
+	if len(errs) > 0 {
+		errs = append(errs, shutdownExporters(ctx, telemetry.exporters))
+		return nil, errors.Join(errs...)
 	}
 
-	// if we do not have any trace exporter config but exporters to use, we default
-	// to report to all configured exporters.
-	if cfg.Trace != nil && cfg.Trace.Exporters == nil {
-		cfg.Trace = &config.TraceConfig{
-			Exporters:  make([]string, 0, len(spanExporters)),
-			SampleRate: 1,
-		}
-		exporters := cfg.Trace.Exporters
-		for spanExporter := range spanExporters {
-			exporters = append(exporters, spanExporter)
-		}
+	cfg, err = withDefaultExporters(cfg, metricExporters, spanExporters, logExporters)
+	if err == nil {
+		err = validateSignals(cfg, metricExporters, spanExporters, logExporters)
+	}
+	if err != nil {
+		return nil, errors.Join(err, shutdownExporters(ctx, telemetry.exporters))
 	}
 
-	res := sdkresource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceName(cfg.ServiceName))
 	if cfg.Trace != nil {
-		err := initTraceProvider(ctx, res, cfg.Trace, spanExporters)
+		telemetry.tracerProvider, err = initTraceProvider(res, cfg.Trace, spanExporters)
 		if err != nil {
-			return err
+			return nil, errors.Join(err, telemetry.Shutdown(ctx))
 		}
 	}
 	if cfg.Metrics != nil {
-		err := initMeterProvider(ctx, res, cfg.Metrics, metricExporters, views)
+		telemetry.meterProvider, err = initMeterProvider(res, cfg.Metrics, metricExporters, views)
 		if err != nil {
-			return err
+			return nil, errors.Join(err, telemetry.Shutdown(ctx))
 		}
 	}
+	if cfg.Logs != nil {
+		telemetry.loggerProvider, err = initLoggerProvider(res, cfg.Logs, logExporters)
+		if err != nil {
+			return nil, errors.Join(err, telemetry.Shutdown(ctx))
+		}
+	}
+	if telemetry.tracerProvider != nil {
+		otel.SetTracerProvider(telemetry.tracerProvider)
+	}
+	if telemetry.meterProvider != nil {
+		otel.SetMeterProvider(telemetry.meterProvider)
+	}
+	if telemetry.loggerProvider != nil {
+		global.SetLoggerProvider(telemetry.loggerProvider)
+	}
 	otel.SetTextMapPropagator(prop)
-	return nil
+	return telemetry, nil
+}
+
+// shutdowners lists the exporter instances holding resources to release,
+// each instance once even when it exports several signals.
+func shutdowners(metricExporters map[string]exporter.MetricReader, spanExporters map[string]exporter.SpanExporter, logExporters map[string]exporter.LogExporter) []exporter.Shutdowner {
+	var list []exporter.Shutdowner
+	seen := map[exporter.Shutdowner]bool{}
+	add := func(instance interface{}) {
+		if s, ok := instance.(exporter.Shutdowner); ok && !seen[s] {
+			seen[s] = true
+			list = append(list, s)
+		}
+	}
+	for _, metricExporter := range metricExporters {
+		add(metricExporter)
+	}
+	for _, spanExporter := range spanExporters {
+		add(spanExporter)
+	}
+	for _, logExporter := range logExporters {
+		add(logExporter)
+	}
+	return list
 }
 
-func initTraceProvider(ctx context.Context, resource *sdkresource.Resource, traceCfg *config.TraceConfig, spanExporters map[string]exporter.SpanExporter) error {
+// instances returns the exporter instances by name.
+func instances(metricExporters map[string]exporter.MetricReader, spanExporters map[string]exporter.SpanExporter, logExporters map[string]exporter.LogExporter) map[string]interface{} {
+	list := make(map[string]interface{}, len(metricExporters)+len(spanExporters)+len(logExporters))
+	for name, metricExporter := range metricExporters {
+		list[name] = metricExporter
+	}
+	for name, spanExporter := range spanExporters {
+		list[name] = spanExporter
+	}
+	for name, logExporter := range logExporters {
+		list[name] = logExporter
+	}
+	return list
+}
+
+// withDefaultExporters returns a copy of cfg where the signals configured
+// without exporter list are routed to all the exporters supporting them, in
+// the order of their declaration. The other settings of the signals are kept.
+// It fails when no exporter supports such a signal, which would not be
+// exported.
+func withDefaultExporters(cfg *config.Config, metricExporters map[string]exporter.MetricReader, spanExporters map[string]exporter.SpanExporter, logExporters map[string]exporter.LogExporter) (*config.Config, error) {
+	out := *cfg
+	var errs []error
+	supporting := func(signal, field string, supports func(string) bool) []string {
+		names := []string{}
+		for _, e := range cfg.Exporters {
+			if supports(e.Name) {
+				names = append(names, e.Name)
+			}
+		}
+		if len(names) == 0 {
+			errs = append(errs, &config.ValidationError{Field: field, Err: config.ErrNoSupportingExporter})
+			return names
+		}
+		log.Info().Str("signal", signal).Strs("exporters", names).Msg("no exporters configured for the signal, defaulting to all the exporters supporting it")
+		return names
+	}
+	if cfg.Metrics != nil && cfg.Metrics.Exporters == nil {
+		metrics := *cfg.Metrics
+		metrics.Exporters = supporting("metrics", "metrics.exporters", func(name string) bool {
+			_, ok := metricExporters[name]
+			return ok
+		})
+		out.Metrics = &metrics
+	}
+	if cfg.Trace != nil && cfg.Trace.Exporters == nil {
+		trace := *cfg.Trace
+		// like when the trace config was replaced by the defaults, an unset
+		// sample rate samples all the traces instead of none
+		if trace.SampleRate == 0 {
+			trace.SampleRate = 1
+		}
+		trace.Exporters = supporting("traces", "traces.exporters", func(name string) bool {
+			_, ok := spanExporters[name]
+			return ok
+		})
+		out.Trace = &trace
+	}
+	if cfg.Logs != nil && cfg.Logs.Exporters == nil {
+		logs := *cfg.Logs
+		logs.Exporters = supporting("logs", "logs.exporters", func(name string) bool {
+			_, ok := logExporters[name]
+			return ok
+		})
+		out.Logs = &logs
+	}
+	return &out, errors.Join(errs...)
+}
+
+// validateSignals checks that the exporters of each signal support it, which
+// is only known once they are created.
+func validateSignals(cfg *config.Config, metricExporters map[string]exporter.MetricReader, spanExporters map[string]exporter.SpanExporter, logExporters map[string]exporter.LogExporter) error {
+	var errs []error
+	unsupported := func(field string, names []string, supported func(string) bool) {
+		for i, name := range names {
+			if !supported(name) {
+				errs = append(errs, &config.ValidationError{
+					Field: fmt.Sprintf("%s[%d]", field, i),
+					Err:   fmt.Errorf("%w: %s", config.ErrUnsupportedSignal, name),
+				})
+			}
+		}
+	}
+	if cfg.Metrics != nil {
+		unsupported("metrics.exporters", cfg.Metrics.Exporters, func(name string) bool {
+			_, ok := metricExporters[name]
+			return ok
+		})
+	}
+	if cfg.Trace != nil {
+		unsupported("traces.exporters", cfg.Trace.Exporters, func(name string) bool {
+			_, ok := spanExporters[name]
+			return ok
+		})
+	}
+	if cfg.Logs != nil {
+		unsupported("logs.exporters", cfg.Logs.Exporters, func(name string) bool {
+			_, ok := logExporters[name]
+			return ok
+		})
+	}
+	return errors.Join(errs...)
+}
+
+func initTraceProvider(resource *sdkresource.Resource, traceCfg *config.TraceConfig, spanExporters map[string]exporter.SpanExporter) (*sdktrace.TracerProvider, error) {
 	traceOpts := []sdktrace.TracerProviderOption{sdktrace.WithResource(resource)}
 	for _, exporterName := range traceCfg.Exporters {
 		spanExporter, ok := spanExporters[exporterName]
 		if !ok {
-			return fmt.Errorf("span exporter: %s provided in trace config does not exist. (spanExporters: %#v)", exporterName, spanExporters)
+			return nil, fmt.Errorf("span exporter: %s provided in trace config does not exist. (spanExporters: %#v)", exporterName, spanExporters)
 		}
-		//nolint:godox
-		// Todo: Add support for exposing BatchSpanProcessorOption config.
-		// Currently default exporter timeout, retry, max queue size configs are being used.
-		traceOpts = append(traceOpts, sdktrace.WithBatcher(spanExporter.SpanExporter()))
-	}
-
-	var tracerProvider trace.TracerProvider = nooptrace.NewTracerProvider()
-	var sdkTracerProvider *sdktrace.TracerProvider
-	if len(traceOpts) > 0 {
-		samplerOpt := sdktrace.WithSampler(sdktrace.ParentBased(
-			sdktrace.TraceIDRatioBased(traceCfg.SampleRate)))
-		traceOpts = append(traceOpts, samplerOpt)
-		sdkTracerProvider = sdktrace.NewTracerProvider(traceOpts...)
-		go func() {
-			<-ctx.Done()
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			_ = sdkTracerProvider.Shutdown(ctx)
-			cancel()
-		}()
-		tracerProvider = sdkTracerProvider
-	}
-	otel.SetTracerProvider(tracerProvider)
-	return nil
+		processor := spanProcessor(spanExporter.SpanExporter(), traceCfg.Processors[exporterName])
+		traceOpts = append(traceOpts, sdktrace.WithSpanProcessor(processor))
+	}
+
+	traceSampler, err := sampler.New(traceCfg)
+	if err != nil {
+		return nil, err
+	}
+	traceOpts = append(traceOpts, sdktrace.WithSampler(traceSampler))
+	return sdktrace.NewTracerProvider(traceOpts...), nil
 }
 
-func initMeterProvider(ctx context.Context, resource *sdkresource.Resource, cfg *config.MetricsConfig, metricExporters map[string]exporter.MetricReader, views []sdkmetric.View) error {
+func spanProcessor(spanExporter sdktrace.SpanExporter, cfg config.SpanProcessorConfig) sdktrace.SpanProcessor {
+	if cfg.Synchronous {
+		return sdktrace.NewSimpleSpanProcessor(spanExporter)
+	}
+	var opts []sdktrace.BatchSpanProcessorOption
+	if cfg.MaxQueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(cfg.MaxQueueSize))
+	}
+	if cfg.MaxExportBatchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(cfg.MaxExportBatchSize))
+	}
+	if cfg.BatchTimeoutMs > 0 {
+		opts = append(opts, sdktrace.WithBatchTimeout(time.Duration(cfg.BatchTimeoutMs)*time.Millisecond))
+	}
+	if cfg.ExportTimeoutMs > 0 {
+		opts = append(opts, sdktrace.WithExportTimeout(time.Duration(cfg.ExportTimeoutMs)*time.Millisecond))
+	}
+	if cfg.Blocking {
+		opts = append(opts, sdktrace.WithBlocking())
+	}
+	return sdktrace.NewBatchSpanProcessor(spanExporter, opts...)
+}
+
+func initMeterProvider(resource *sdkresource.Resource, cfg *config.MetricsConfig, metricExporters map[string]exporter.MetricReader, views []sdkmetric.View) (*sdkmetric.MeterProvider, error) {
 	metricOpts := []sdkmetric.Option{sdkmetric.WithResource(resource)}
 	if len(views) > 0 {
 		metricOpts = append(metricOpts, sdkmetric.WithView(views...))
@@ -125,25 +313,21 @@ func initMeterProvider(ctx context.Context, resource *sdkresource.Resource, cfg
 	for _, exporterName := range cfg.Exporters {
 		metricExporter, ok := metricExporters[exporterName]
 		if !ok {
-			return fmt.Errorf("metric exporter %s provided in metrics config does not exist. (metricExporters: %#v)", metricExporter, metricExporters)
+			return nil, fmt.Errorf("metric exporter %s provided in metrics config does not exist. (metricExporters: %#v)", exporterName, metricExporters)
 		}
-		//nolint:godox
-		// TODO: pass reporting period for use-cases where metrics is pushed instead of scraped?
 		metricOpts = append(metricOpts, sdkmetric.WithReader(metricExporter.MetricReader()))
 	}
+	return sdkmetric.NewMeterProvider(metricOpts...), nil
+}
 
-	var meterProvider metric.MeterProvider = noopmetric.NewMeterProvider()
-	var sdkMetricProvider *sdkmetric.MeterProvider
-	if len(metricOpts) > 0 {
-		sdkMetricProvider = sdkmetric.NewMeterProvider(metricOpts...)
-		go func() {
-			<-ctx.Done()
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			_ = sdkMetricProvider.Shutdown(ctx)
-			cancel()
-		}()
-		meterProvider = sdkMetricProvider
+func initLoggerProvider(resource *sdkresource.Resource, cfg *config.LogsConfig, logExporters map[string]exporter.LogExporter) (*sdklog.LoggerProvider, error) {
+	logOpts := []sdklog.LoggerProviderOption{sdklog.WithResource(resource)}
+	for _, exporterName := range cfg.Exporters {
+		logExporter, ok := logExporters[exporterName]
+		if !ok {
+			return nil, fmt.Errorf("log exporter %s provided in logs config does not exist. (logExporters: %#v)", exporterName, logExporters)
+		}
+		logOpts = append(logOpts, sdklog.WithProcessor(sdklog.NewBatchProcessor(logExporter.LogExporter())))
 	}
-	otel.SetMeterProvider(meterProvider)
-	return nil
+	return sdklog.NewLoggerProvider(logOpts...), nil
 }
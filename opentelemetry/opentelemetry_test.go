@@ -4,17 +4,28 @@ import (
 	"context"
 	"go.opentelemetry.io/otel/sdk/instrumentation"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter"
 	"github.com/razorpay/golib/opentelemetry/exporter/opentelemetry"
 	"github.com/razorpay/golib/opentelemetry/exporter/prometheus"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestObsWithValidConfig(t *testing.T) {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
 	cfg := &config.Config{
 		ServiceName: "test-service",
 		Exporters: []config.Exporter{
@@ -22,7 +33,7 @@ func TestObsWithValidConfig(t *testing.T) {
 				Name: "prom",
 				Kind: prometheus.ExporterKey,
 				Config: map[string]interface{}{
-					"port":            9092,
+					"port":            0,
 					"process_metrics": true,
 					"go_metrics":      true,
 				},
@@ -79,3 +90,263 @@ func TestObsWithNoExporterConfig(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorContains(t, err, "no exporters declared")
 }
+
+func TestSetupAndShutdown(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		ServiceName: "test-service",
+		Exporters: []config.Exporter{
+			{
+				Name: "prom",
+				Kind: prometheus.ExporterKey,
+				Config: map[string]interface{}{
+					"port": 0,
+				},
+			},
+			{
+				Name: "otel",
+				Kind: opentelemetry.ExporterKey,
+			},
+		},
+		Metrics: &config.MetricsConfig{
+			Exporters: []string{"prom"},
+		},
+		Trace: &config.TraceConfig{
+			Exporters:  []string{"otel"},
+			SampleRate: 1.0,
+		},
+		Logs: &config.LogsConfig{
+			Exporters: []string{"otel"},
+		},
+		Propagators: []string{"tracecontext", "baggage", "b3"},
+	}
+	telemetry, err := Setup(ctx, cfg, nil)
+	require.NoError(t, err)
+	require.NotNil(t, telemetry.TracerProvider())
+	require.NotNil(t, telemetry.MeterProvider())
+	require.NotNil(t, telemetry.LoggerProvider())
+	require.Contains(t, otel.GetTextMapPropagator().Fields(), "b3")
+	require.NoError(t, telemetry.ForceFlush(ctx))
+	require.NoError(t, telemetry.Shutdown(ctx))
+	require.NoError(t, telemetry.Shutdown(ctx))
+}
+
+func TestSpanProcessor(t *testing.T) {
+	ctx := context.Background()
+	spanExporter := tracetest.NewInMemoryExporter()
+	synchronous := spanProcessor(spanExporter, config.SpanProcessorConfig{Synchronous: true})
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(synchronous))
+	_, span := tracerProvider.Tracer("test").Start(ctx, "synchronous")
+	span.End()
+	require.Len(t, spanExporter.GetSpans(), 1)
+	require.NoError(t, tracerProvider.Shutdown(ctx))
+
+	spanExporter = tracetest.NewInMemoryExporter()
+	batch := spanProcessor(spanExporter, config.SpanProcessorConfig{
+		MaxQueueSize:       10,
+		MaxExportBatchSize: 5,
+		BatchTimeoutMs:     60000,
+		Blocking:           true,
+	})
+	tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(batch))
+	_, span = tracerProvider.Tracer("test").Start(ctx, "batched")
+	span.End()
+	require.Empty(t, spanExporter.GetSpans())
+	require.NoError(t, tracerProvider.ForceFlush(ctx))
+	require.Len(t, spanExporter.GetSpans(), 1)
+	require.NoError(t, tracerProvider.Shutdown(ctx))
+}
+
+func TestObsWithUnknownPropagator(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName: "test-service",
+		Exporters: []config.Exporter{
+			{
+				Name: "otel",
+				Kind: opentelemetry.ExporterKey,
+			},
+		},
+		Trace:       &config.TraceConfig{},
+		Propagators: []string{"tracecontext", "w3c"},
+	}
+	_, err := Setup(context.Background(), cfg, nil)
+	require.ErrorContains(t, err, "propagator w3c not found")
+}
+
+func TestSetupDisabled(t *testing.T) {
+	telemetry, err := Setup(context.Background(), &config.Config{Disabled: true}, nil)
+	require.NoError(t, err)
+	require.Nil(t, telemetry.TracerProvider())
+	require.Nil(t, telemetry.MeterProvider())
+	require.NoError(t, telemetry.Shutdown(context.Background()))
+}
+
+func TestSetupWithUnsupportedSignal(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName: "test-service",
+		Exporters: []config.Exporter{
+			{
+				Name:   "prom",
+				Kind:   prometheus.ExporterKey,
+				Config: map[string]interface{}{"port": 0},
+			},
+		},
+		Trace: &config.TraceConfig{Exporters: []string{"prom"}},
+	}
+	_, err := Setup(context.Background(), cfg, nil)
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "traces.exporters[0]", validationErr.Field)
+	require.ErrorIs(t, err, config.ErrUnsupportedSignal)
+}
+
+func TestSetupWithoutSupportingExporter(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName: "test-service",
+		Exporters: []config.Exporter{
+			{
+				Name:   "prom",
+				Kind:   prometheus.ExporterKey,
+				Config: map[string]interface{}{"port": 0},
+			},
+		},
+		Metrics: &config.MetricsConfig{},
+		Trace:   &config.TraceConfig{SampleRate: 1},
+	}
+	_, err := Setup(context.Background(), cfg, nil)
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "traces.exporters", validationErr.Field)
+	require.ErrorIs(t, err, config.ErrNoSupportingExporter)
+}
+
+func TestSetupWithExporterFactory(t *testing.T) {
+	ctx := context.Background()
+	var created []map[string]interface{}
+	factory := func(ctx context.Context, cfg map[string]interface{}) (interface{}, error) {
+		created = append(created, cfg)
+		return prometheus.NewFactory()(ctx, cfg)
+	}
+	cfg := &config.Config{
+		ServiceName: "test-service",
+		Exporters: []config.Exporter{
+			{
+				Name:   "prom",
+				Kind:   prometheus.ExporterKey,
+				Config: map[string]interface{}{"serve": false},
+			},
+		},
+		Metrics: &config.MetricsConfig{},
+	}
+	telemetry, err := Setup(ctx, cfg, nil, WithExporterFactory(prometheus.ExporterKey, factory))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, telemetry.Shutdown(ctx)) }()
+	require.Equal(t, []map[string]interface{}{{"serve": false}}, created)
+	require.IsType(t, &prometheus.Collector{}, telemetry.Exporter("prom"))
+}
+
+func TestSetupWithOneExporterForAllSignals(t *testing.T) {
+	ctx := context.Background()
+	var paths sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths.Store(r.URL.Path, true)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		ServiceName: "test-service",
+		Exporters: []config.Exporter{
+			{
+				Name: "otel",
+				Kind: opentelemetry.ExporterKey,
+				Config: map[string]interface{}{
+					"host":     serverURL.Hostname(),
+					"port":     port,
+					"protocol": "http/protobuf",
+				},
+			},
+		},
+		Metrics: &config.MetricsConfig{Exporters: []string{"otel"}},
+		Trace:   &config.TraceConfig{Exporters: []string{"otel"}, SampleRate: 1.0},
+		Logs:    &config.LogsConfig{Exporters: []string{"otel"}},
+	}
+	telemetry, err := Setup(ctx, cfg, nil)
+	require.NoError(t, err)
+	require.NotNil(t, telemetry.TracerProvider())
+	require.NotNil(t, telemetry.MeterProvider())
+	require.NotNil(t, telemetry.LoggerProvider())
+	require.Len(t, telemetry.exporters, 1)
+	require.IsType(t, &opentelemetry.Collector{}, telemetry.Exporter("otel"))
+	require.Nil(t, telemetry.Exporter("prom"))
+
+	_, span := telemetry.TracerProvider().Tracer("test").Start(ctx, "span")
+	span.End()
+	counter, err := telemetry.MeterProvider().Meter("test").Int64Counter("counter")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+	record := log.Record{}
+	record.SetBody(log.StringValue("message"))
+	telemetry.LoggerProvider().Logger("test").Emit(ctx, record)
+
+	require.NoError(t, telemetry.Shutdown(ctx))
+	for _, path := range []string{"/v1/traces", "/v1/metrics", "/v1/logs"} {
+		_, ok := paths.Load(path)
+		require.True(t, ok, path)
+	}
+}
+
+func TestSetupWithDefaultExporters(t *testing.T) {
+	ctx := context.Background()
+	ratio := 0.25
+	cfg := &config.Config{
+		ServiceName: "test-service",
+		Exporters: []config.Exporter{
+			{
+				Name:   "prom",
+				Kind:   prometheus.ExporterKey,
+				Config: map[string]interface{}{"port": 0},
+			},
+			{
+				Name: "otel",
+				Kind: opentelemetry.ExporterKey,
+			},
+		},
+		Metrics: &config.MetricsConfig{},
+		Trace: &config.TraceConfig{
+			SampleRate: 0.5,
+			Sampler:    &config.SamplerConfig{Type: "traceidratio", Ratio: &ratio},
+		},
+		Logs: &config.LogsConfig{},
+	}
+
+	metricExporters, spanExporters, logExporters, errs := exporter.CreateInstances(ctx, cfg.Exporters)
+	require.Empty(t, errs)
+	defer func() {
+		require.NoError(t, shutdownExporters(ctx, shutdowners(metricExporters, spanExporters, logExporters)))
+	}()
+
+	out, err := withDefaultExporters(cfg, metricExporters, spanExporters, logExporters)
+	require.NoError(t, err)
+	require.Equal(t, []string{"prom", "otel"}, out.Metrics.Exporters)
+	require.Equal(t, []string{"otel"}, out.Trace.Exporters)
+	require.Equal(t, []string{"otel"}, out.Logs.Exporters)
+	require.Equal(t, 0.5, out.Trace.SampleRate)
+	require.Same(t, cfg.Trace.Sampler, out.Trace.Sampler)
+
+	// the configuration of the caller is left untouched
+	require.Nil(t, cfg.Metrics.Exporters)
+	require.Nil(t, cfg.Trace.Exporters)
+	require.Nil(t, cfg.Logs.Exporters)
+
+	// an unset sample rate samples all the traces
+	cfg.Trace = &config.TraceConfig{}
+	out, err = withDefaultExporters(cfg, metricExporters, spanExporters, logExporters)
+	require.NoError(t, err)
+	require.Equal(t, []string{"otel"}, out.Trace.Exporters)
+	require.Equal(t, 1.0, out.Trace.SampleRate)
+	require.Zero(t, cfg.Trace.SampleRate)
+}
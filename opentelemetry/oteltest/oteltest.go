@@ -0,0 +1,193 @@
+// Package oteltest records the telemetry of the code under test in memory,
+// in place of the exporters of the configuration, to assert on it.
+//
+// The providers are registered globally, so the tests using [Setup] must not
+// run in parallel with each other.
+package oteltest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/razorpay/golib/opentelemetry"
+	"github.com/razorpay/golib/opentelemetry/config"
+	"github.com/razorpay/golib/opentelemetry/exporter/memory"
+	"github.com/razorpay/golib/opentelemetry/sampler"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// ServiceName is the service name of the recorded telemetry.
+const ServiceName = "oteltest"
+
+const exporterName = "memory"
+
+// Recorder holds the telemetry recorded since [Setup].
+type Recorder struct {
+	t         testing.TB
+	telemetry *opentelemetry.Telemetry
+	collector *memory.Collector
+}
+
+// Setup registers the providers of all the signals, exporting to memory,
+// for the duration of the test. Every span is sampled and exported when it
+// ends. The providers are shut down and the previous ones restored by
+// t.Cleanup.
+func Setup(t testing.TB, views ...sdkmetric.View) *Recorder {
+	t.Helper()
+	cfg := &config.Config{
+		ServiceName: ServiceName,
+		Exporters:   []config.Exporter{{Name: exporterName, Kind: memory.ExporterKey}},
+		Trace: &config.TraceConfig{
+			Exporters:  []string{exporterName},
+			Sampler:    &config.SamplerConfig{Type: sampler.AlwaysOn},
+			Processors: map[string]config.SpanProcessorConfig{exporterName: {Synchronous: true}},
+		},
+		Metrics: &config.MetricsConfig{Exporters: []string{exporterName}},
+		Logs:    &config.LogsConfig{Exporters: []string{exporterName}},
+	}
+
+	tracerProvider := otel.GetTracerProvider()
+	meterProvider := otel.GetMeterProvider()
+	loggerProvider := global.GetLoggerProvider()
+	propagator := otel.GetTextMapPropagator()
+	telemetry, err := opentelemetry.Setup(context.Background(), cfg, views)
+	if err != nil {
+		t.Fatalf("oteltest: setup telemetry: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := telemetry.Shutdown(context.Background()); err != nil {
+			t.Errorf("oteltest: shutdown telemetry: %v", err)
+		}
+		otel.SetTracerProvider(tracerProvider)
+		otel.SetMeterProvider(meterProvider)
+		global.SetLoggerProvider(loggerProvider)
+		otel.SetTextMapPropagator(propagator)
+	})
+	return &Recorder{
+		t:         t,
+		telemetry: telemetry,
+		collector: telemetry.Exporter(exporterName).(*memory.Collector),
+	}
+}
+
+// Telemetry returns the providers registered by [Setup].
+func (r *Recorder) Telemetry() *opentelemetry.Telemetry {
+	return r.telemetry
+}
+
+// Spans returns the spans ended so far.
+func (r *Recorder) Spans() tracetest.SpanStubs {
+	return r.collector.Spans()
+}
+
+// Metrics collects the current state of the metrics.
+func (r *Recorder) Metrics() metricdata.ResourceMetrics {
+	r.t.Helper()
+	rm, err := r.collector.Metrics(context.Background())
+	if err != nil {
+		r.t.Fatalf("oteltest: collect metrics: %v", err)
+	}
+	return rm
+}
+
+// Logs returns the log records emitted so far.
+func (r *Recorder) Logs() []sdklog.Record {
+	r.t.Helper()
+	if err := r.telemetry.LoggerProvider().ForceFlush(context.Background()); err != nil {
+		r.t.Fatalf("oteltest: flush logs: %v", err)
+	}
+	return r.collector.Logs()
+}
+
+// Reset forgets the spans and log records recorded so far.
+func (r *Recorder) Reset() {
+	r.collector.Reset()
+}
+
+// AssertSpan returns the first ended span named name having all the attrs,
+// among others. The test is stopped if there is none.
+func (r *Recorder) AssertSpan(name string, attrs ...attribute.KeyValue) tracetest.SpanStub {
+	r.t.Helper()
+	spans := r.Spans()
+	for _, span := range spans {
+		if span.Name == name && hasAttributes(attribute.NewSet(span.Attributes...), attrs) {
+			return span
+		}
+	}
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, fmt.Sprintf("%s%v", span.Name, span.Attributes))
+	}
+	r.t.Fatalf("oteltest: no span %s with attributes %v, recorded spans: [%s]", name, attrs, strings.Join(names, ", "))
+	return tracetest.SpanStub{}
+}
+
+// AssertCounter checks that the counter named name has the value, summed
+// over the data points having all the attrs, among others. The test is
+// stopped otherwise.
+func (r *Recorder) AssertCounter(name string, value float64, attrs ...attribute.KeyValue) {
+	r.t.Helper()
+	for _, scope := range r.Metrics().ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != name {
+				continue
+			}
+			got, ok := counterValue(m.Data, attrs)
+			if !ok {
+				r.t.Fatalf("oteltest: metric %s is a %T, not a counter", name, m.Data)
+			}
+			if got != value {
+				r.t.Fatalf("oteltest: counter %s with attributes %v is %v, want %v", name, attrs, got, value)
+			}
+			return
+		}
+	}
+	r.t.Fatalf("oteltest: no counter %s", name)
+}
+
+// counterValue sums the data points of a monotonic sum having the attrs.
+func counterValue(data metricdata.Aggregation, attrs []attribute.KeyValue) (float64, bool) {
+	var total float64
+	switch sum := data.(type) {
+	case metricdata.Sum[int64]:
+		if !sum.IsMonotonic {
+			return 0, false
+		}
+		for _, point := range sum.DataPoints {
+			if hasAttributes(point.Attributes, attrs) {
+				total += float64(point.Value)
+			}
+		}
+	case metricdata.Sum[float64]:
+		if !sum.IsMonotonic {
+			return 0, false
+		}
+		for _, point := range sum.DataPoints {
+			if hasAttributes(point.Attributes, attrs) {
+				total += point.Value
+			}
+		}
+	default:
+		return 0, false
+	}
+	return total, true
+}
+
+func hasAttributes(set attribute.Set, attrs []attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		value, ok := set.Value(attr.Key)
+		if !ok || value != attr.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package oteltest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+	recorder := Setup(t)
+
+	ctx, parent := otel.Tracer("test").Start(ctx, "parent")
+	_, child := otel.Tracer("test").Start(ctx, "child")
+	child.SetAttributes(attribute.String("http.method", "GET"), attribute.Int("http.status_code", 200))
+	child.End()
+	parent.End()
+
+	require.Len(t, recorder.Spans(), 2)
+	span := recorder.AssertSpan("child", attribute.String("http.method", "GET"))
+	require.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	recorder.AssertSpan("parent")
+
+	counter, err := otel.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("route", "/a")))
+	counter.Add(ctx, 3, metric.WithAttributes(attribute.String("route", "/b")))
+	recorder.AssertCounter("requests", 5)
+	recorder.AssertCounter("requests", 3, attribute.String("route", "/b"))
+
+	var record log.Record
+	record.SetBody(log.StringValue("hello"))
+	global.GetLoggerProvider().Logger("test").Emit(ctx, record)
+	logs := recorder.Logs()
+	require.Len(t, logs, 1)
+	require.Equal(t, "hello", logs[0].Body().AsString())
+
+	recorder.Reset()
+	require.Empty(t, recorder.Spans())
+	require.Empty(t, recorder.Logs())
+}
+
+func TestSetupCleanup(t *testing.T) {
+	tracerProvider := otel.GetTracerProvider()
+	var recorder *Recorder
+	t.Run("setup", func(t *testing.T) {
+		recorder = Setup(t)
+		require.NotEqual(t, tracerProvider, otel.GetTracerProvider())
+	})
+	require.Equal(t, tracerProvider, otel.GetTracerProvider())
+
+	_, span := recorder.Telemetry().TracerProvider().Tracer("test").Start(context.Background(), "after")
+	span.End()
+	require.Empty(t, recorder.Spans())
+}
@@ -0,0 +1,87 @@
+// Package propagator holds the registry of the propagators which can be
+// selected by name in the configuration.
+package propagator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/contrib/propagators/ot"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	TraceContext = "tracecontext"
+	Baggage      = "baggage"
+	B3           = "b3"
+	B3Multi      = "b3multi"
+	Jaeger       = "jaeger"
+	XRay         = "xray"
+	OTTrace      = "ottrace"
+	// None disables the propagation, it can not be combined with other propagators.
+	None = "none"
+)
+
+var ErrDuplicatePropagator = errors.New("propagator already registered")
+
+// DefaultPropagators are used when no propagator is configured.
+var DefaultPropagators = []string{TraceContext, Baggage}
+
+// Factory is the function type to obtain a propagator.
+type Factory func() propagation.TextMapPropagator
+
+var (
+	mu        = new(sync.RWMutex)
+	factories = map[string]Factory{
+		TraceContext: func() propagation.TextMapPropagator { return propagation.TraceContext{} },
+		Baggage:      func() propagation.TextMapPropagator { return propagation.Baggage{} },
+		B3: func() propagation.TextMapPropagator {
+			return b3.New(b3.WithInjectEncoding(b3.B3SingleHeader))
+		},
+		B3Multi: func() propagation.TextMapPropagator {
+			return b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader))
+		},
+		Jaeger:  func() propagation.TextMapPropagator { return jaeger.Jaeger{} },
+		XRay:    func() propagation.TextMapPropagator { return xray.Propagator{} },
+		OTTrace: func() propagation.TextMapPropagator { return ot.OT{} },
+	}
+)
+
+// Register makes a custom propagator available under name. It fails if
+// the name is already taken.
+func Register(name string, factory Factory) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := factories[name]; ok || name == None {
+		return fmt.Errorf("%w: %s", ErrDuplicatePropagator, name)
+	}
+	factories[name] = factory
+	return nil
+}
+
+// New creates the composite propagator of the named propagators, in the
+// given order. The [DefaultPropagators] are used when names is empty.
+func New(names []string) (propagation.TextMapPropagator, error) {
+	if len(names) == 0 {
+		names = DefaultPropagators
+	}
+	if len(names) == 1 && names[0] == None {
+		return propagation.NewCompositeTextMapPropagator(), nil
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		factory, ok := factories[name]
+		if !ok {
+			return nil, fmt.Errorf("propagator %s not found", name)
+		}
+		propagators = append(propagators, factory())
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
@@ -0,0 +1,52 @@
+package propagator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNew(t *testing.T) {
+	prop, err := New(nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, prop.Fields())
+
+	prop, err = New([]string{B3, B3Multi, Jaeger, XRay, OTTrace})
+	require.NoError(t, err)
+	require.Contains(t, prop.Fields(), "b3")
+	require.Contains(t, prop.Fields(), "x-b3-traceid")
+	require.Contains(t, prop.Fields(), "uber-trace-id")
+	require.Contains(t, prop.Fields(), "X-Amzn-Trace-Id")
+	require.Contains(t, prop.Fields(), "ot-tracer-traceid")
+
+	prop, err = New([]string{None})
+	require.NoError(t, err)
+	require.Empty(t, prop.Fields())
+
+	_, err = New([]string{"w3c"})
+	require.ErrorContains(t, err, "propagator w3c not found")
+}
+
+func TestExtractB3(t *testing.T) {
+	prop, err := New([]string{TraceContext, B3})
+	require.NoError(t, err)
+	carrier := propagation.MapCarrier{"b3": "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"}
+	spanCtx := trace.SpanContextFromContext(prop.Extract(context.Background(), carrier))
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanCtx.TraceID().String())
+	require.True(t, spanCtx.IsSampled())
+}
+
+func TestRegister(t *testing.T) {
+	err := Register("custom", func() propagation.TextMapPropagator { return propagation.Baggage{} })
+	require.NoError(t, err)
+	_, err = New([]string{TraceContext, "custom"})
+	require.NoError(t, err)
+
+	err = Register("custom", func() propagation.TextMapPropagator { return propagation.Baggage{} })
+	require.ErrorIs(t, err, ErrDuplicatePropagator)
+	err = Register(TraceContext, func() propagation.TextMapPropagator { return propagation.TraceContext{} })
+	require.ErrorIs(t, err, ErrDuplicatePropagator)
+}
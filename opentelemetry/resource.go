@@ -0,0 +1,136 @@
+package opentelemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	EnvDetector       = "env"
+	HostDetector      = "host"
+	ProcessDetector   = "process"
+	OSDetector        = "os"
+	ContainerDetector = "container"
+	K8sDetector       = "k8s"
+)
+
+// k8sEnvAttributes are the environment variables read by the k8s detector,
+// to be populated with the downward API.
+var k8sEnvAttributes = map[string]attribute.Key{
+	"K8S_POD_NAME":        semconv.K8SPodNameKey,
+	"K8S_POD_UID":         semconv.K8SPodUIDKey,
+	"K8S_NAMESPACE_NAME":  semconv.K8SNamespaceNameKey,
+	"K8S_NODE_NAME":       semconv.K8SNodeNameKey,
+	"K8S_CONTAINER_NAME":  semconv.K8SContainerNameKey,
+	"K8S_DEPLOYMENT_NAME": semconv.K8SDeploymentNameKey,
+}
+
+// newResource creates the resource of the telemetry. Its semconv version is
+// the one of the SDK detectors, resources with different schema URLs failing
+// to merge.
+func newResource(ctx context.Context, cfg *config.Config) (*sdkresource.Resource, error) {
+	if cfg.Resource == nil {
+		return sdkresource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName)), nil
+	}
+
+	var opts []sdkresource.Option
+	for _, detector := range cfg.Resource.Detectors {
+		opt, err := detectorOption(detector)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+	attrs := make([]attribute.KeyValue, 0, len(cfg.Resource.Attributes)+1)
+	for key, value := range cfg.Resource.Attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	opts = append(opts, sdkresource.WithAttributes(attrs...))
+	if cfg.ServiceName != "" {
+		opts = append(opts, sdkresource.WithAttributes(semconv.ServiceName(cfg.ServiceName)))
+	}
+	opts = append(opts, sdkresource.WithSchemaURL(semconv.SchemaURL))
+
+	res, err := sdkresource.New(ctx, opts...)
+	if errors.Is(err, sdkresource.ErrPartialResource) {
+		// some attributes could not be detected, the others are still useful.
+		otel.Handle(err)
+		err = nil
+	}
+	return res, err
+}
+
+func detectorOption(name string) (sdkresource.Option, error) {
+	switch name {
+	case EnvDetector:
+		return sdkresource.WithFromEnv(), nil
+	case HostDetector:
+		return sdkresource.WithHost(), nil
+	case ProcessDetector:
+		// the command line arguments and the owner are left out as they may be sensitive.
+		return sdkresource.WithDetectors(
+			processDetectors(
+				sdkresource.WithProcessPID(),
+				sdkresource.WithProcessExecutableName(),
+				sdkresource.WithProcessExecutablePath(),
+				sdkresource.WithProcessRuntimeName(),
+				sdkresource.WithProcessRuntimeVersion(),
+				sdkresource.WithProcessRuntimeDescription(),
+			)...), nil
+	case OSDetector:
+		return sdkresource.WithOS(), nil
+	case ContainerDetector:
+		return sdkresource.WithContainer(), nil
+	case K8sDetector:
+		return sdkresource.WithDetectors(k8sDetector{}), nil
+	default:
+		return nil, fmt.Errorf("unknown resource detector: %s", name)
+	}
+}
+
+// processDetectors unwraps resource options into detectors, so they can
+// be grouped into a single option.
+func processDetectors(opts ...sdkresource.Option) []sdkresource.Detector {
+	detectors := make([]sdkresource.Detector, 0, len(opts))
+	for _, opt := range opts {
+		detectors = append(detectors, optionDetector{opt})
+	}
+	return detectors
+}
+
+type optionDetector struct {
+	opt sdkresource.Option
+}
+
+func (d optionDetector) Detect(ctx context.Context) (*sdkresource.Resource, error) {
+	return sdkresource.New(ctx, d.opt)
+}
+
+// k8sDetector reads the Kubernetes attributes exposed as environment variables.
+type k8sDetector struct{}
+
+func (k8sDetector) Detect(context.Context) (*sdkresource.Resource, error) {
+	var attrs []attribute.KeyValue
+	for env, key := range k8sEnvAttributes {
+		if value, ok := os.LookupEnv(env); ok && value != "" {
+			attrs = append(attrs, key.String(value))
+		}
+	}
+	if len(attrs) == 0 {
+		return sdkresource.Empty(), nil
+	}
+	return sdkresource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
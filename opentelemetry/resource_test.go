@@ -0,0 +1,81 @@
+package opentelemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestNewResource(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "service.name=from-env,deployment.environment=dev,team=payments")
+	t.Setenv("K8S_POD_NAME", "api-6d4cf56db6-x2x9p")
+	t.Setenv("K8S_NAMESPACE_NAME", "api")
+
+	res, err := newResource(context.Background(), &config.Config{
+		ServiceName: "test-service",
+		Resource: &config.ResourceConfig{
+			Attributes: map[string]string{
+				"deployment.environment": "prod",
+				"service.version":        "1.2.3",
+			},
+			Detectors: []string{EnvDetector, K8sDetector, ProcessDetector},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, semconv.SchemaURL, res.SchemaURL())
+
+	expected := map[attribute.Key]string{
+		semconv.ServiceNameKey:      "test-service",
+		semconv.ServiceVersionKey:   "1.2.3",
+		"deployment.environment":    "prod",
+		"team":                      "payments",
+		semconv.K8SPodNameKey:       "api-6d4cf56db6-x2x9p",
+		semconv.K8SNamespaceNameKey: "api",
+	}
+	set := res.Set()
+	for key, value := range expected {
+		actual, ok := set.Value(key)
+		require.True(t, ok, key)
+		require.Equal(t, value, actual.AsString(), key)
+	}
+	_, ok := set.Value(semconv.ProcessPIDKey)
+	require.True(t, ok)
+	_, ok = set.Value(semconv.ProcessCommandArgsKey)
+	require.False(t, ok)
+}
+
+func TestNewResourceWithoutConfig(t *testing.T) {
+	res, err := newResource(context.Background(), &config.Config{ServiceName: "test-service"})
+	require.NoError(t, err)
+	require.Equal(t, sdkresource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("test-service")), res)
+}
+
+func TestNewResourceWithUnknownDetector(t *testing.T) {
+	_, err := newResource(context.Background(), &config.Config{
+		ServiceName: "test-service",
+		Resource:    &config.ResourceConfig{Detectors: []string{"gcp"}},
+	})
+	require.EqualError(t, err, "unknown resource detector: gcp")
+}
+
+func TestNewResourceMergesWithDefault(t *testing.T) {
+	configs := []*config.Config{
+		{ServiceName: "test-service"},
+		{ServiceName: "test-service", Resource: &config.ResourceConfig{
+			Detectors: []string{EnvDetector, HostDetector, ProcessDetector, OSDetector, ContainerDetector, K8sDetector},
+		}},
+	}
+	for _, cfg := range configs {
+		res, err := newResource(context.Background(), cfg)
+		require.NoError(t, err)
+		// resources with different schema URLs cannot be merged.
+		_, err = sdkresource.Merge(sdkresource.Default(), res)
+		require.NoError(t, err)
+	}
+}
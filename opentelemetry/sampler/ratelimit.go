@@ -0,0 +1,73 @@
+package sampler
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// maxSpanNames bounds the number of span names having their own budget,
+// the names seen afterwards share a single budget.
+const maxSpanNames = 1000
+
+// rateLimited samples up to a number of traces per second, with a token
+// bucket allowing bursts of one second worth of traces.
+type rateLimited struct {
+	perSecond   float64
+	perSpanName bool
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	overflow *rate.Limiter
+}
+
+func newRateLimited(perSecond float64, perSpanName bool) *rateLimited {
+	return &rateLimited{
+		perSecond:   perSecond,
+		perSpanName: perSpanName,
+		limiters:    map[string]*rate.Limiter{},
+		overflow:    newLimiter(perSecond),
+	}
+}
+
+func newLimiter(perSecond float64) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(perSecond), int(math.Max(1, math.Ceil(perSecond))))
+}
+
+func (s *rateLimited) limiter(name string) *rate.Limiter {
+	if !s.perSpanName {
+		return s.overflow
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limiter, ok := s.limiters[name]
+	if !ok {
+		if len(s.limiters) >= maxSpanNames {
+			return s.overflow
+		}
+		limiter = newLimiter(s.perSecond)
+		s.limiters[name] = limiter
+	}
+	return limiter
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *rateLimited) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if s.perSecond > 0 && s.limiter(p.Name).Allow() {
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description implements sdktrace.Sampler.
+func (s *rateLimited) Description() string {
+	return fmt.Sprintf("RateLimited{%g,perSpanName:%t}", s.perSecond, s.perSpanName)
+}
@@ -0,0 +1,100 @@
+package sampler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// ruleBased delegates the decision to the sampler of the first matching
+// rule, or to the fallback sampler.
+type ruleBased struct {
+	rules    []rule
+	fallback sdktrace.Sampler
+}
+
+type rule struct {
+	spanName   *regexp.Regexp
+	attributes map[attribute.Key]*regexp.Regexp
+	sampler    sdktrace.Sampler
+}
+
+func newRuleBased(rulesCfg []config.SamplingRule, fallback sdktrace.Sampler) (*ruleBased, error) {
+	rules := make([]rule, 0, len(rulesCfg))
+	for idx, ruleCfg := range rulesCfg {
+		ratio := 1.0
+		if ruleCfg.Ratio != nil {
+			ratio = *ruleCfg.Ratio
+		}
+		r := rule{
+			attributes: map[attribute.Key]*regexp.Regexp{},
+			sampler:    sdktrace.TraceIDRatioBased(ratio),
+		}
+		var err error
+		if ruleCfg.SpanName != "" {
+			if r.spanName, err = compilePattern(ruleCfg.SpanName); err != nil {
+				return nil, fmt.Errorf("sampling rule (at idx %d): %w", idx, err)
+			}
+		}
+		patterns := map[string]string{}
+		for key, pattern := range ruleCfg.Attributes {
+			patterns[key] = pattern
+		}
+		if ruleCfg.HTTPRoute != "" {
+			patterns[string(semconv.HTTPRouteKey)] = ruleCfg.HTTPRoute
+		}
+		for key, pattern := range patterns {
+			if r.attributes[attribute.Key(key)], err = compilePattern(pattern); err != nil {
+				return nil, fmt.Errorf("sampling rule (at idx %d): %w", idx, err)
+			}
+		}
+		rules = append(rules, r)
+	}
+	return &ruleBased{rules: rules, fallback: fallback}, nil
+}
+
+// compilePattern compiles a pattern where * matches any sequence of characters.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	return regexp.Compile("^" + quoted + "$")
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *ruleBased) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, r := range s.rules {
+		if r.matches(p) {
+			return r.sampler.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+// Description implements sdktrace.Sampler.
+func (s *ruleBased) Description() string {
+	return fmt.Sprintf("RuleBased{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+func (r *rule) matches(p sdktrace.SamplingParameters) bool {
+	if r.spanName != nil && !r.spanName.MatchString(p.Name) {
+		return false
+	}
+	for key, pattern := range r.attributes {
+		matched := false
+		for _, kv := range p.Attributes {
+			if kv.Key == key {
+				matched = pattern.MatchString(kv.Value.Emit())
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+// Package sampler creates the trace sampler described by the configuration.
+package sampler
+
+import (
+	"fmt"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// The sampler types, declared in the config package which validates them.
+const (
+	AlwaysOn                = config.SamplerAlwaysOn
+	AlwaysOff               = config.SamplerAlwaysOff
+	TraceIDRatio            = config.SamplerTraceIDRatio
+	ParentBasedAlwaysOn     = config.SamplerParentBasedAlwaysOn
+	ParentBasedAlwaysOff    = config.SamplerParentBasedAlwaysOff
+	ParentBasedTraceIDRatio = config.SamplerParentBasedTraceIDRatio
+	RateLimited             = config.SamplerRateLimited
+	ParentBasedRateLimited  = config.SamplerParentBasedRateLimited
+)
+
+// New creates the sampler of the trace config. Without sampler config,
+// the traces are sampled at the SampleRate, following the parent decision.
+func New(cfg *config.TraceConfig) (sdktrace.Sampler, error) {
+	if cfg.Sampler == nil {
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate)), nil
+	}
+	samplerCfg := cfg.Sampler
+	ratio := cfg.SampleRate
+	if samplerCfg.Ratio != nil {
+		ratio = *samplerCfg.Ratio
+	}
+	samplerType := samplerCfg.Type
+	if samplerType == "" {
+		samplerType = ParentBasedTraceIDRatio
+	}
+
+	var root sdktrace.Sampler
+	parentBased := true
+	switch samplerType {
+	case AlwaysOn:
+		root, parentBased = sdktrace.AlwaysSample(), false
+	case AlwaysOff:
+		root, parentBased = sdktrace.NeverSample(), false
+	case TraceIDRatio:
+		root, parentBased = sdktrace.TraceIDRatioBased(ratio), false
+	case ParentBasedAlwaysOn:
+		root = sdktrace.AlwaysSample()
+	case ParentBasedAlwaysOff:
+		root = sdktrace.NeverSample()
+	case ParentBasedTraceIDRatio:
+		root = sdktrace.TraceIDRatioBased(ratio)
+	case RateLimited, ParentBasedRateLimited:
+		root = newRateLimited(samplerCfg.TracesPerSecond, samplerCfg.PerSpanName)
+	default:
+		return nil, fmt.Errorf("unknown sampler type: %s", samplerType)
+	}
+
+	if len(samplerCfg.Rules) > 0 {
+		var err error
+		root, err = newRuleBased(samplerCfg.Rules, root)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !parentBased {
+		return root, nil
+	}
+	if samplerType == RateLimited {
+		// the budget is for the root spans, the other spans follow their
+		// parent for the sampled traces to be complete.
+		return sdktrace.ParentBased(root), nil
+	}
+
+	var opts []sdktrace.ParentBasedSamplerOption
+	policies := []struct {
+		name   string
+		option func(sdktrace.Sampler) sdktrace.ParentBasedSamplerOption
+	}{
+		{samplerCfg.RemoteParentSampled, sdktrace.WithRemoteParentSampled},
+		{samplerCfg.RemoteParentNotSampled, sdktrace.WithRemoteParentNotSampled},
+		{samplerCfg.LocalParentSampled, sdktrace.WithLocalParentSampled},
+		{samplerCfg.LocalParentNotSampled, sdktrace.WithLocalParentNotSampled},
+	}
+	for _, policy := range policies {
+		if policy.name == "" {
+			continue
+		}
+		policySampler, err := policySampler(policy.name, ratio)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, policy.option(policySampler))
+	}
+	return sdktrace.ParentBased(root, opts...), nil
+}
+
+func policySampler(name string, ratio float64) (sdktrace.Sampler, error) {
+	switch name {
+	case AlwaysOn:
+		return sdktrace.AlwaysSample(), nil
+	case AlwaysOff:
+		return sdktrace.NeverSample(), nil
+	case TraceIDRatio:
+		return sdktrace.TraceIDRatioBased(ratio), nil
+	default:
+		return nil, fmt.Errorf("unknown parent policy: %s", name)
+	}
+}
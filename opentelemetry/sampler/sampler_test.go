@@ -0,0 +1,131 @@
+package sampler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/razorpay/golib/opentelemetry/config"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ratio(r float64) *float64 {
+	return &r
+}
+
+func shouldSample(ctx context.Context, s sdktrace.Sampler, name string, attrs ...attribute.KeyValue) bool {
+	result := s.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: ctx,
+		TraceID:       trace.TraceID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		Name:          name,
+		Attributes:    attrs,
+	})
+	return result.Decision == sdktrace.RecordAndSample
+}
+
+func remoteParent(sampled bool) context.Context {
+	cfg := trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, Remote: true}
+	if sampled {
+		cfg.TraceFlags = trace.FlagsSampled
+	}
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(cfg))
+}
+
+func TestNewWithoutSamplerConfig(t *testing.T) {
+	s, err := New(&config.TraceConfig{SampleRate: 1})
+	require.NoError(t, err)
+	require.True(t, shouldSample(context.Background(), s, "root"))
+	require.False(t, shouldSample(remoteParent(false), s, "child"))
+}
+
+func TestNewWithTypes(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(&config.TraceConfig{Sampler: &config.SamplerConfig{Type: AlwaysOn}})
+	require.NoError(t, err)
+	require.True(t, shouldSample(remoteParent(false), s, "child"))
+
+	s, err = New(&config.TraceConfig{Sampler: &config.SamplerConfig{Type: AlwaysOff}})
+	require.NoError(t, err)
+	require.False(t, shouldSample(remoteParent(true), s, "child"))
+
+	s, err = New(&config.TraceConfig{SampleRate: 1, Sampler: &config.SamplerConfig{Type: TraceIDRatio, Ratio: ratio(0)}})
+	require.NoError(t, err)
+	require.False(t, shouldSample(ctx, s, "root"))
+
+	s, err = New(&config.TraceConfig{Sampler: &config.SamplerConfig{
+		Type:                   ParentBasedAlwaysOff,
+		RemoteParentSampled:    AlwaysOff,
+		RemoteParentNotSampled: AlwaysOn,
+	}})
+	require.NoError(t, err)
+	require.False(t, shouldSample(ctx, s, "root"))
+	require.False(t, shouldSample(remoteParent(true), s, "child"))
+	require.True(t, shouldSample(remoteParent(false), s, "child"))
+
+	_, err = New(&config.TraceConfig{Sampler: &config.SamplerConfig{Type: "sometimes"}})
+	require.ErrorContains(t, err, "unknown sampler type")
+	_, err = New(&config.TraceConfig{Sampler: &config.SamplerConfig{LocalParentSampled: "sometimes"}})
+	require.ErrorContains(t, err, "unknown parent policy")
+}
+
+func TestNewWithRules(t *testing.T) {
+	s, err := New(&config.TraceConfig{Sampler: &config.SamplerConfig{
+		Type:  ParentBasedTraceIDRatio,
+		Ratio: ratio(1),
+		Rules: []config.SamplingRule{
+			{HTTPRoute: "/payments/*", Attributes: map[string]string{"error": "true"}},
+			{HTTPRoute: "/health", Ratio: ratio(0)},
+			{SpanName: "cron.*", Ratio: ratio(0)},
+		},
+	}})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.True(t, shouldSample(ctx, s, "GET", attribute.String("http.route", "/payments/{id}"), attribute.Bool("error", true)))
+	require.False(t, shouldSample(ctx, s, "GET", attribute.String("http.route", "/health")))
+	require.False(t, shouldSample(ctx, s, "cron.settlements"))
+	require.True(t, shouldSample(ctx, s, "GET", attribute.String("http.route", "/orders")))
+	// the rules do not apply to the spans having a parent
+	require.True(t, shouldSample(remoteParent(true), s, "cron.settlements"))
+	require.Contains(t, s.Description(), "RuleBased{rules:3")
+}
+
+func TestNewWithRateLimit(t *testing.T) {
+	s, err := New(&config.TraceConfig{Sampler: &config.SamplerConfig{
+		Type:            ParentBasedRateLimited,
+		TracesPerSecond: 2,
+	}})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.True(t, shouldSample(ctx, s, "GET /payments"))
+	require.True(t, shouldSample(ctx, s, "GET /orders"))
+	require.False(t, shouldSample(ctx, s, "GET /refunds"))
+	// the spans having a sampled parent are not limited
+	require.True(t, shouldSample(remoteParent(true), s, "GET /refunds"))
+
+	s, err = New(&config.TraceConfig{Sampler: &config.SamplerConfig{
+		Type:            RateLimited,
+		TracesPerSecond: 1,
+		PerSpanName:     true,
+	}})
+	require.NoError(t, err)
+	require.True(t, shouldSample(ctx, s, "GET /payments"))
+	require.False(t, shouldSample(ctx, s, "GET /payments"))
+	require.True(t, shouldSample(ctx, s, "GET /orders"))
+	// the children of the sampled traces do not use the budget
+	require.True(t, shouldSample(remoteParent(true), s, "GET /payments"))
+	require.False(t, shouldSample(remoteParent(false), s, "GET /refunds"))
+	require.True(t, shouldSample(ctx, s, "GET /refunds"))
+}
+
+func TestRateLimitedSpanNamesBound(t *testing.T) {
+	s := newRateLimited(1, true)
+	for i := 0; i < maxSpanNames+10; i++ {
+		s.limiter(fmt.Sprintf("span-%d", i))
+	}
+	require.Len(t, s.limiters, maxSpanNames)
+	require.Same(t, s.overflow, s.limiter("span-overflow"))
+}
@@ -0,0 +1,94 @@
+package opentelemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/razorpay/golib/opentelemetry/exporter"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Telemetry is the handle over the providers and exporters created by
+// [Setup]. It allows the caller to flush and shut down telemetry
+// deterministically, e.g. when receiving SIGTERM.
+type Telemetry struct {
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+	loggerProvider *sdklog.LoggerProvider
+	exporters      []exporter.Shutdowner
+	instances      map[string]interface{}
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// TracerProvider returns the SDK tracer provider, nil if traces are not configured.
+func (t *Telemetry) TracerProvider() *sdktrace.TracerProvider {
+	return t.tracerProvider
+}
+
+// MeterProvider returns the SDK meter provider, nil if metrics are not configured.
+func (t *Telemetry) MeterProvider() *sdkmetric.MeterProvider {
+	return t.meterProvider
+}
+
+// LoggerProvider returns the SDK logger provider, nil if logs are not configured.
+func (t *Telemetry) LoggerProvider() *sdklog.LoggerProvider {
+	return t.loggerProvider
+}
+
+// Exporter returns the instance of the exporter declared with name, nil
+// if there is none. The instance is the one created by the factory of its
+// kind, e.g. a *prometheus.Collector for the prometheus kind.
+func (t *Telemetry) Exporter(name string) interface{} {
+	return t.instances[name]
+}
+
+// ForceFlush exports all the telemetry data buffered by the providers.
+func (t *Telemetry) ForceFlush(ctx context.Context) error {
+	var errs []error
+	if t.tracerProvider != nil {
+		errs = append(errs, t.tracerProvider.ForceFlush(ctx))
+	}
+	if t.meterProvider != nil {
+		errs = append(errs, t.meterProvider.ForceFlush(ctx))
+	}
+	if t.loggerProvider != nil {
+		errs = append(errs, t.loggerProvider.ForceFlush(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown flushes the pending telemetry data and releases the providers
+// and exporters. It returns once every component is stopped or ctx is done,
+// with the errors of all the components joined. Subsequent calls return the
+// result of the first one.
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	t.shutdownOnce.Do(func() {
+		var errs []error
+		if t.tracerProvider != nil {
+			errs = append(errs, t.tracerProvider.Shutdown(ctx))
+		}
+		if t.meterProvider != nil {
+			errs = append(errs, t.meterProvider.Shutdown(ctx))
+		}
+		if t.loggerProvider != nil {
+			errs = append(errs, t.loggerProvider.Shutdown(ctx))
+		}
+		errs = append(errs, shutdownExporters(ctx, t.exporters))
+		t.shutdownErr = errors.Join(errs...)
+	})
+	return t.shutdownErr
+}
+
+func shutdownExporters(ctx context.Context, exporters []exporter.Shutdowner) error {
+	var errs []error
+	for _, e := range exporters {
+		errs = append(errs, e.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}